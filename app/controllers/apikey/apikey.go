@@ -0,0 +1,120 @@
+package apikey
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type APIKey struct {
+	apiKeyService *services.APIKey
+	logs          *zerolog.Logger
+}
+
+func New(apiKeyService *services.APIKey, logs *zerolog.Logger) *APIKey {
+	return &APIKey{
+		apiKeyService: apiKeyService,
+		logs:          logs,
+	}
+}
+
+var errScope = errors.New(" The scope cannot be wider than yours. ")
+
+// Get controller to get the list of API keys, within the scope of the caller
+func (a *APIKey) Get(ctx *gin.Context) {
+	scope := middlewares.Scope(ctx)
+	keys := []models.APIKey{}
+	for _, key := range a.apiKeyService.List() {
+		if key.Scope.Within(scope) {
+			keys = append(keys, key)
+		}
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "APIKeys",
+			TotalCount: len(keys),
+			Count:      len(keys),
+			Offset:     1,
+		},
+		Data: keys,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Create controller to issue an API key, its value is only returned in this response
+func (a *APIKey) Create(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "apikey.Create.Done",
+		BadRequest:          "apikey.Create.BadRequest",
+		Forbidden:           "apikey.Create.Forbidden",
+		InternalServerError: "apikey.Create.Error",
+	}
+
+	var spec models.APIKeySpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"name": spec.Name, "role": string(spec.Role), "scope": middlewares.AuditLabels(spec.Scope), "expires_in": spec.ExpiresIn})
+
+	if !spec.Scope.Within(middlewares.Scope(ctx)) {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errScope))
+		return
+	}
+
+	created, err := a.apiKeyService.Create(middlewares.Subject(ctx), spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "APIKey",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: created,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Revoke controller to disable an API key
+func (a *APIKey) Revoke(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "apikey.Revoke.Done",
+		NotFound:            "apikey.Revoke.NotFound",
+		Conflict:            "apikey.Revoke.Conflict",
+		InternalServerError: "apikey.Revoke.Error",
+	}
+
+	// Keys out of the scope of the caller are reported as not found
+	key, err := a.apiKeyService.Get(ctx.Param("id"))
+	if err == nil && !key.Scope.Within(middlewares.Scope(ctx)) {
+		err = errors.New(" API key " + ctx.Param("id") + " not found. ")
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
+	}
+	if err == nil {
+		_, err = a.apiKeyService.Revoke(key.ID)
+	}
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, "API key revoked: "+key.ID))
+}
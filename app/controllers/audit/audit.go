@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/functions"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Audit struct {
+	auditService *services.Audit
+	logs         *zerolog.Logger
+}
+
+func New(auditService *services.Audit, logs *zerolog.Logger) *Audit {
+	return &Audit{
+		auditService: auditService,
+		logs:         logs,
+	}
+}
+
+// Get controller to get the audit entries, of the callers within the scope of the caller
+func (a *Audit) Get(ctx *gin.Context) {
+	var params models.QueryParams
+
+	params.Parse(ctx)
+	messageTypes := &models.MessageTypes{
+		OK:                  "audit.Search.Found",
+		BadRequest:          "audit.Search.BadRequest",
+		NotFound:            "audit.Search.NotFound",
+		InternalServerError: "audit.Search.Error",
+	}
+
+	if params.Export != "" && !functions.Contains(common.ExportFormats, params.Export) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Unknown export format. ")))
+		return
+	}
+
+	all, err := a.auditService.List(&params)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+	scope := middlewares.Scope(ctx)
+	entries := all[:0]
+	for _, entry := range all {
+		if entry.Scope.Within(scope) {
+			entries = append(entries, entry)
+		}
+	}
+
+	// Exports hold the whole result set
+	if params.Export != "" {
+		if err := common.Export(ctx, "audit", params.Export, entries, params.Columns); err != nil {
+			a.logs.Error().Err(err).Msg("")
+		}
+		return
+	}
+
+	totalCount := len(entries)
+	if totalCount == 0 {
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+		return
+	}
+
+	low, high, err := common.Page(&params, totalCount)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
+	}
+
+	sendingEntries, err := common.ProjectList(entries[low:high], params.Columns)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Audit",
+			TotalCount: totalCount,
+			Count:      high - low,
+			Offset:     low + 1,
+		},
+		Data: sendingEntries,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
@@ -1,10 +1,15 @@
 package common
 
 import (
+	"adminDocker/app/functions"
 	"adminDocker/app/models"
 	"adminDocker/app/server"
+	"adminDocker/app/services"
+	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/gin-gonic/gin"
 )
 
@@ -26,3 +31,88 @@ func Version(c *gin.Context) {
 func SendResponse(c *gin.Context, status int, response interface{}) {
 	c.JSON(status, response)
 }
+
+// ProjectList keeps only the given columns (dot paths) of every item of a list.
+// The list is returned unchanged without columns.
+func ProjectList(items interface{}, columns []string) (interface{}, error) {
+	if len(columns) == 0 {
+		return items, nil
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(b, &objects); err != nil {
+		return nil, err
+	}
+	for i, obj := range objects {
+		objects[i] = functions.Project(obj, columns)
+	}
+	return objects, nil
+}
+
+// Page returns the bounds of the page asked by the offset and count parameters
+// in a result set of total items, 100 items when no count is given.
+func Page(params *models.QueryParams, total int) (int, int, error) {
+	low := params.Offset - 1
+	if low < 0 {
+		low = 0
+	}
+
+	// Available CountMax calculation
+	maxCount := params.Count
+	if maxCount == 0 {
+		maxCount = 100
+	}
+
+	high := maxCount + low
+	if high > total {
+		high = total
+	}
+
+	if low > high {
+		return 0, 0, errors.New(" Offset cannot be higher than count. ")
+	}
+	return low, high, nil
+}
+
+// StatusFromError maps a Docker or service error to the matching HTTP status.
+func StatusFromError(err error) int {
+	switch {
+	case errdefs.IsNotFound(err):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrContainerState), errors.Is(err, services.ErrAmbiguousID), errdefs.IsConflict(err):
+		return http.StatusConflict
+	case errdefs.IsInvalidParameter(err):
+		return http.StatusBadRequest
+	case errdefs.IsUnauthorized(err):
+		return http.StatusUnauthorized
+	case errdefs.IsForbidden(err):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// MessageType returns the message type matching an HTTP status.
+func MessageType(messageTypes *models.MessageTypes, status int) string {
+	switch status {
+	case http.StatusOK:
+		return messageTypes.OK
+	case http.StatusCreated:
+		return messageTypes.Created
+	case http.StatusBadRequest:
+		return messageTypes.BadRequest
+	case http.StatusUnauthorized:
+		return messageTypes.Unauthorized
+	case http.StatusForbidden:
+		return messageTypes.Forbidden
+	case http.StatusNotFound:
+		return messageTypes.NotFound
+	case http.StatusConflict:
+		return messageTypes.Conflict
+	default:
+		return messageTypes.InternalServerError
+	}
+}
@@ -0,0 +1,173 @@
+package common
+
+import (
+	"adminDocker/app/functions"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportFormats lists the formats accepted by the export parameter.
+var ExportFormats = []string{"csv", "ndjson", "xlsx"}
+
+var exportContentTypes = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"ndjson": "application/x-ndjson",
+	"xlsx":   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Export sends a list as a file attachment named after name and the current time.
+// Only the given columns are exported, in their order, when set.
+// The format must be one of ExportFormats.
+func Export(c *gin.Context, name string, format string, items interface{}, columns []string) error {
+	contentType := exportContentTypes[format]
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(b, &objects); err != nil {
+		return err
+	}
+
+	name = exportName(name)
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "ndjson" {
+		encoder := json.NewEncoder(c.Writer)
+		for _, obj := range objects {
+			if len(columns) > 0 {
+				obj = functions.Project(obj, columns)
+			}
+			if err := encoder.Encode(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	headers, rows := exportTable(objects, columns)
+	if format == "csv" {
+		// XLSX cells are written as strings, only a CSV file may run formulas
+		writer := csv.NewWriter(c.Writer)
+		for _, row := range append([][]string{headers}, rows...) {
+			escaped := make([]string, len(row))
+			for i, value := range row {
+				escaped[i] = escapeFormula(value)
+			}
+			if err := writer.Write(escaped); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return writeXLSX(c, name, headers, rows)
+}
+
+// exportTable flattens objects to rows. Without columns, every leaf found is a column.
+func exportTable(objects []map[string]interface{}, columns []string) ([]string, [][]string) {
+	flats := make([]map[string]interface{}, len(objects))
+	for i, obj := range objects {
+		flats[i] = functions.Flatten(obj)
+	}
+
+	headers := columns
+	if len(headers) == 0 {
+		seen := make(map[string]bool)
+		for _, flat := range flats {
+			for path := range flat {
+				if !seen[path] {
+					seen[path] = true
+					headers = append(headers, path)
+				}
+			}
+		}
+		sort.Strings(headers)
+	}
+
+	rows := make([][]string, len(objects))
+	for i, obj := range objects {
+		row := make([]string, len(headers))
+		for j, header := range headers {
+			// Label keys hold dots, the leaves are read as flattened. Columns may
+			// also name an object, or differ in case, those are read by path.
+			value, ok := flats[i][header]
+			if !ok {
+				value, _ = functions.GetPath(obj, header)
+			}
+			row[j] = functions.ValueString(value)
+		}
+		rows[i] = row
+	}
+	return headers, rows
+}
+
+// escapeFormula prefixes with a quote the values spreadsheets would run as formulas.
+func escapeFormula(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+// exportName keeps the letters, digits, dots, dashes and underscores of a name, for a
+// filename and a sheet name, which refuses :\/?*[].
+func exportName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-", r) {
+			return r
+		}
+		return '-'
+	}, name)
+	if name == "" {
+		return "export"
+	}
+	return name
+}
+
+func writeXLSX(c *gin.Context, sheet string, headers []string, rows [][]string) error {
+	// A sheet name is 31 characters at most
+	if runes := []rune(sheet); len(runes) > 31 {
+		sheet = string(runes[:31])
+	}
+	file := excelize.NewFile()
+	defer file.Close()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	for i, values := range append([][]string{headers}, rows...) {
+		row := make([]interface{}, len(values))
+		for j, value := range values {
+			row[j] = value
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := stream.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return file.Write(c.Writer)
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"adminDocker/app/server"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+// Stream pushes JSON messages to a client over Server-Sent Events or WebSocket.
+// Its context is cancelled as soon as the client goes away.
+type Stream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	gin    *gin.Context
+	conn   *websocket.Conn
+}
+
+// IsStreamRequest tells if the client asked for a WebSocket or an SSE stream.
+func IsStreamRequest(c *gin.Context) bool {
+	return websocket.IsWebSocketUpgrade(c.Request) || c.GetHeader("Accept") == "text/event-stream"
+}
+
+// IsWebSocketRequest tells if the client asked for a WebSocket.
+func IsWebSocketRequest(c *gin.Context) bool {
+	return websocket.IsWebSocketUpgrade(c.Request)
+}
+
+// UpgradeWebSocket upgrades the request to a WebSocket read and written by the caller.
+func UpgradeWebSocket(c *gin.Context) (*websocket.Conn, error) {
+	return upgrader.Upgrade(c.Writer, c.Request, nil)
+}
+
+// OpenStream upgrades the request to WebSocket when asked to, and falls back to Server-Sent Events.
+func OpenStream(c *gin.Context) (*Stream, error) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	s := &Stream{ctx: ctx, cancel: cancel, gin: c}
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		s.conn = conn
+
+		// Read until the client closes the connection
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		return s, nil
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	return s, nil
+}
+
+// Context returns the context of the stream, done when the client disconnects.
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
+
+// Send pushes a message, named event for SSE clients.
+func (s *Stream) Send(event string, data interface{}) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if s.conn != nil {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := s.conn.WriteJSON(data); err != nil {
+			s.cancel()
+			return err
+		}
+		return nil
+	}
+	s.gin.SSEvent(event, data)
+	s.gin.Writer.Flush()
+	return nil
+}
+
+// Close ends the stream, with a normal closure frame for WebSocket clients.
+func (s *Stream) Close() {
+	defer s.cancel()
+	if s.conn == nil {
+		return
+	}
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	_ = s.conn.Close()
+}
+
+// checkOrigin accepts the origin configured in ALLOW_ORIGIN.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	srv := server.GetServer()
+	if origin == "" || srv == nil || srv.Origin == "*" {
+		return true
+	}
+	return origin == srv.Origin
+}
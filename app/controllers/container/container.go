@@ -2,23 +2,30 @@ package container
 
 import (
 	"adminDocker/app/controllers/common"
+	"adminDocker/app/functions"
+	"adminDocker/app/middlewares"
 	"adminDocker/app/models"
 	"adminDocker/app/services"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/docker/docker/api/types"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 )
 
 type Container struct {
 	containerService *services.Container
+	statsCollector   *services.StatsCollector
 	logs             *zerolog.Logger
 }
 
-func New(containerService *services.Container, logs *zerolog.Logger) *Container {
+func New(containerService *services.Container, statsCollector *services.StatsCollector, logs *zerolog.Logger) *Container {
 	return &Container{
 		containerService: containerService,
+		statsCollector:   statsCollector,
 		logs:             logs,
 	}
 }
@@ -35,43 +42,57 @@ func (c *Container) Get(ctx *gin.Context) {
 		InternalServerError: "container.Search.Error",
 	}
 
-	containers, err := c.containerService.ListDocker()
-	if err != nil {
-		common.SendResponse(ctx, http.StatusInternalServerError, models.KnownError(http.StatusInternalServerError, messageTypes.InternalServerError, err))
+	if params.Export != "" && !functions.Contains(common.ExportFormats, params.Export) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Unknown export format. ")))
+		return
 	}
-	totalCount := len(containers)
-	if totalCount == 0 {
-		status := http.StatusNotFound
-		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+
+	containers, err := c.containerService.List(ctx.Request.Context(), &params, middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
 	}
 
-	low := params.Offset - 1
-	if low == -1 {
-		low = 0
+	if params.GroupBy != "" {
+		c.sendGroups(ctx, &params, containers, messageTypes)
+		return
 	}
 
-	// Available CountMax calculation
-	maxCount := params.Count
-	if maxCount == 0 {
-		maxCount = 100
+	// Exports hold the whole result set
+	if params.Export != "" {
+		if err := common.Export(ctx, "dockers", params.Export, containers, params.Columns); err != nil {
+			c.logs.Error().Err(err).Msg("")
+		}
+		return
 	}
 
-	high := maxCount + low
-	if high > totalCount {
-		high = totalCount
+	totalCount := len(containers)
+	if totalCount == 0 {
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+		return
 	}
 
-	if low > high {
+	low, high, err := common.Page(&params, totalCount)
+	if err != nil {
 		status := http.StatusBadRequest
-		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Offset cannot be higher than count. ")))
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
 	}
 
-	sendingContainers := containers[low:high]
+	sendingContainers, err := common.ProjectList(containers[low:high], params.Columns)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
 
 	meta := models.MetaResponse{
 		ObjectName: "Dockers",
 		TotalCount: totalCount,
-		Count:      len(sendingContainers),
+		Count:      high - low,
 		Offset:     low + 1,
 	}
 
@@ -82,3 +103,83 @@ func (c *Container) Get(ctx *gin.Context) {
 
 	common.SendResponse(ctx, http.StatusOK, response)
 }
+
+// sendGroups sends the number of containers, and their IDs with members=true, per value of the col parameter.
+func (c *Container) sendGroups(ctx *gin.Context, params *models.QueryParams, containers []types.Container, messageTypes *models.MessageTypes) {
+	members, _ := strconv.ParseBool(ctx.Query("members"))
+	groups, err := services.GroupContainers(containers, params.GroupBy, members)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
+
+	if params.Export != "" {
+		if err := common.Export(ctx, "dockers-"+strings.ToLower(params.GroupBy), params.Export, groups, nil); err != nil {
+			c.logs.Error().Err(err).Msg("")
+		}
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "DockersGroups",
+			TotalCount: len(containers),
+			Count:      len(groups),
+			Offset:     1,
+			GroupBy:    params.GroupBy,
+		},
+		Data: groups,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// InScope aborts the requests on a container out of the scope of the caller,
+// and names the container for the audit log.
+func (c *Container) InScope(ctx *gin.Context) {
+	id, name, err := c.containerService.Authorize(ctx.Request.Context(), ctx.Param("id"), middlewares.Scope(ctx))
+	if err != nil {
+		ctx.Set(middlewares.ContainerKey, ctx.Param("id"))
+		// Without a scope, the handlers report the missing containers themselves
+		if len(middlewares.Scope(ctx)) > 0 {
+			status := common.StatusFromError(err)
+			ctx.AbortWithStatusJSON(status, models.KnownError(status, "container.Scope.NotFound", err))
+			return
+		}
+	} else {
+		ctx.Set(middlewares.ContainerKey, id)
+		ctx.Set(middlewares.ContainerNameKey, name)
+	}
+	ctx.Next()
+}
+
+// GetOne controller to get the detail of a container by ID, short ID or name,
+// with the networks it is attached to
+func (c *Container) GetOne(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.Get.Found",
+		BadRequest:          "container.Get.BadRequest",
+		NotFound:            "container.Get.NotFound",
+		Conflict:            "container.Get.Conflict",
+		InternalServerError: "container.Get.Error",
+	}
+
+	detail, err := c.containerService.Detail(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Docker",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: detail,
+	}
+
+	common.SendResponse(ctx, http.StatusOK, response)
+}
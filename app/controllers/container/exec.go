@@ -0,0 +1,254 @@
+package container
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// ExecInteractive controller to open a terminal in a container over WebSocket.
+// The command (cmd, /bin/sh by default), tty (true by default), user, workdir, rows and cols
+// are query parameters. Clients send stdin and resize messages, and receive stdout,
+// stderr and, once the command ends, exit messages.
+func (c *Container) ExecInteractive(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.Exec.Done",
+		BadRequest:          "container.Exec.BadRequest",
+		NotFound:            "container.Exec.NotFound",
+		Conflict:            "container.Exec.Conflict",
+		InternalServerError: "container.Exec.Error",
+	}
+
+	if !common.IsWebSocketRequest(ctx) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" WebSocket upgrade expected. ")))
+		return
+	}
+
+	spec, err := execSpec(ctx)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+
+	// The exec runs for the session, not for the request
+	session, err := c.containerService.Exec(context.Background(), ctx.Param("id"), spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+	defer session.Close()
+	ctx.Set(middlewares.AuditKey, true)
+
+	conn, err := common.UpgradeWebSocket(ctx)
+	if err != nil {
+		c.logs.Error().Err(err).Msg("")
+		return
+	}
+	defer conn.Close()
+
+	var mu sync.Mutex
+	send := func(message models.ExecMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(message)
+	}
+
+	// Input and resizes from the client, the session is closed when the client leaves
+	left := make(chan struct{})
+	go func() {
+		defer session.Close()
+		defer close(left)
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				_, _ = session.Write(data)
+				continue
+			}
+			var message models.ExecMessage
+			if err := json.Unmarshal(data, &message); err != nil {
+				continue
+			}
+			switch message.Type {
+			case "stdin":
+				if _, err := session.Write([]byte(message.Data)); err != nil {
+					return
+				}
+			case "resize":
+				if err := session.Resize(context.Background(), message.Rows, message.Cols); err != nil {
+					c.logs.Debug().Err(err).Msg("")
+				}
+			}
+		}
+	}()
+
+	stdout, stderr := &execWriter{kind: "stdout", send: send}, &execWriter{kind: "stderr", send: send}
+	err = session.Output(stdout, stderr)
+	if err != nil {
+		c.logs.Error().Err(err).Msg("")
+	}
+	_ = stdout.Flush()
+	_ = stderr.Flush()
+
+	select {
+	case <-left:
+		// Without its streams the command would keep running
+		if err := session.Kill(context.Background()); err != nil {
+			c.logs.Error().Err(err).Str("container", ctx.Param("id")).Str("exec", session.ID).Msg("Exec left running after the client left")
+		}
+	default:
+	}
+
+	exitCode, err := session.ExitCode(context.Background())
+	if err != nil {
+		c.logs.Warn().Err(err).Str("exec", session.ID).Msg("")
+		return
+	}
+	c.logs.Info().Str("container", ctx.Param("id")).Str("exec", session.ID).Int("exit_code", exitCode).Msg("Exec session ended")
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"exec_id": session.ID, "exit_code": strconv.Itoa(exitCode)})
+
+	_ = send(models.ExecMessage{Type: "exit", ExitCode: &exitCode})
+	mu.Lock()
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	mu.Unlock()
+}
+
+// Exec controller to run a command to completion and get its outputs and exit code
+func (c *Container) Exec(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.Exec.Done",
+		BadRequest:          "container.Exec.BadRequest",
+		NotFound:            "container.Exec.NotFound",
+		Conflict:            "container.Exec.Conflict",
+		InternalServerError: "container.Exec.Error",
+	}
+
+	var spec models.ExecSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	parameters := map[string]string{"cmd": strings.Join(spec.Cmd, " "), "user": spec.User, "env": middlewares.AuditKeys(spec.Env)}
+	ctx.Set(middlewares.AuditParametersKey, parameters)
+
+	result, err := c.containerService.RunExec(ctx.Request.Context(), ctx.Param("id"), &spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	parameters["exec_id"], parameters["timed_out"] = result.ExecID, strconv.FormatBool(result.TimedOut)
+	if result.ExitCode != nil {
+		parameters["exit_code"] = strconv.Itoa(*result.ExitCode)
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Exec",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: result,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// execSpec reads the exec parameters of the query.
+func execSpec(ctx *gin.Context) (*models.ExecSpec, error) {
+	spec := &models.ExecSpec{
+		Cmd:     ctx.QueryArray("cmd"),
+		User:    ctx.Query("user"),
+		WorkDir: ctx.Query("workdir"),
+		Tty:     true,
+	}
+	// A single cmd holds the whole command line, several ones an argument each
+	if len(spec.Cmd) == 1 {
+		spec.Cmd = strings.Fields(spec.Cmd[0])
+	}
+	if len(spec.Cmd) == 0 {
+		spec.Cmd = []string{"/bin/sh"}
+	}
+
+	var err error
+	if value := ctx.Query("tty"); value != "" {
+		if spec.Tty, err = strconv.ParseBool(value); err != nil {
+			return nil, errors.New(" Invalid tty. ")
+		}
+	}
+	for name, size := range map[string]*uint{"rows": &spec.Height, "cols": &spec.Width} {
+		if value := ctx.Query(name); value != "" {
+			n, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return nil, errors.New(" Invalid " + name + ". ")
+			}
+			*size = uint(n)
+		}
+	}
+	return spec, nil
+}
+
+// execWriter sends the output of an exec session as messages of a type. The
+// messages are text, a rune split across reads is held back until its end arrives.
+type execWriter struct {
+	kind    string
+	send    func(models.ExecMessage) error
+	pending []byte
+}
+
+func (w *execWriter) Write(p []byte) (int, error) {
+	data := append(w.pending, p...)
+	end := len(data) - incompleteRune(data)
+	w.pending = append([]byte(nil), data[end:]...)
+	if end == 0 {
+		return len(p), nil
+	}
+	if err := w.send(models.ExecMessage{Type: w.kind, Data: string(data[:end])}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends the bytes held back, once the output ended.
+func (w *execWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	data := string(w.pending)
+	w.pending = nil
+	return w.send(models.ExecMessage{Type: w.kind, Data: data})
+}
+
+// incompleteRune returns the length of the rune data ends with when it is incomplete, 0 otherwise.
+func incompleteRune(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i > len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if utf8.FullRune(data[i:]) {
+				return 0
+			}
+			return len(data) - i
+		}
+	}
+	return 0
+}
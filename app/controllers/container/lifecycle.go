@@ -0,0 +1,100 @@
+package container
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/models"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Start controller to start a container
+func (c *Container) Start(ctx *gin.Context) {
+	c.lifecycle(ctx, "Start", "container started", func(id string) error {
+		return c.containerService.Start(ctx.Request.Context(), id)
+	})
+}
+
+// Stop controller to stop a container
+func (c *Container) Stop(ctx *gin.Context) {
+	timeout, ok := c.timeout(ctx, "Stop")
+	if !ok {
+		return
+	}
+	c.lifecycle(ctx, "Stop", "container stopped", func(id string) error {
+		return c.containerService.Stop(ctx.Request.Context(), id, timeout, ctx.Query("signal"))
+	})
+}
+
+// Restart controller to restart a container
+func (c *Container) Restart(ctx *gin.Context) {
+	timeout, ok := c.timeout(ctx, "Restart")
+	if !ok {
+		return
+	}
+	c.lifecycle(ctx, "Restart", "container restarted", func(id string) error {
+		return c.containerService.Restart(ctx.Request.Context(), id, timeout, ctx.Query("signal"))
+	})
+}
+
+// Pause controller to pause a container
+func (c *Container) Pause(ctx *gin.Context) {
+	c.lifecycle(ctx, "Pause", "container paused", func(id string) error {
+		return c.containerService.Pause(ctx.Request.Context(), id)
+	})
+}
+
+// Unpause controller to unpause a container
+func (c *Container) Unpause(ctx *gin.Context) {
+	c.lifecycle(ctx, "Unpause", "container unpaused", func(id string) error {
+		return c.containerService.Unpause(ctx.Request.Context(), id)
+	})
+}
+
+// Kill controller to send a signal to a container
+func (c *Container) Kill(ctx *gin.Context) {
+	c.lifecycle(ctx, "Kill", "container killed", func(id string) error {
+		return c.containerService.Kill(ctx.Request.Context(), id, ctx.Query("signal"))
+	})
+}
+
+// lifecycle runs a state-changing action and sends the standard response.
+func (c *Container) lifecycle(ctx *gin.Context, action string, msg string, run func(id string) error) {
+	messageTypes := lifecycleMessageTypes(action)
+	id := ctx.Param("id")
+
+	if err := run(id); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, msg+": "+id))
+}
+
+// timeout reads the optional timeout query parameter, in seconds.
+func (c *Container) timeout(ctx *gin.Context, action string) (*int, bool) {
+	value := ctx.Query("timeout")
+	if value == "" {
+		return nil, true
+	}
+	timeout, err := strconv.Atoi(value)
+	if err != nil || timeout < -1 {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, lifecycleMessageTypes(action).BadRequest, errors.New(" Invalid timeout. ")))
+		return nil, false
+	}
+	return &timeout, true
+}
+
+func lifecycleMessageTypes(action string) *models.MessageTypes {
+	return &models.MessageTypes{
+		OK:                  "container." + action + ".Done",
+		BadRequest:          "container." + action + ".BadRequest",
+		NotFound:            "container." + action + ".NotFound",
+		Conflict:            "container." + action + ".Conflict",
+		InternalServerError: "container." + action + ".Error",
+	}
+}
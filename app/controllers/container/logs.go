@@ -0,0 +1,137 @@
+package container
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/models"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/gin-gonic/gin"
+)
+
+// Logs returned as a JSON array are held in memory, their tail is bounded.
+const (
+	defaultLogsTail = 100
+	maxLogsTail     = 10000
+)
+
+// Logs controller to read the logs of a container.
+// Lines are returned as a JSON array, or streamed over SSE or WebSocket when
+// the client asks for it or sets follow=true.
+func (c *Container) Logs(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.Logs.Found",
+		BadRequest:          "container.Logs.BadRequest",
+		NotFound:            "container.Logs.NotFound",
+		Conflict:            "container.Logs.Conflict",
+		InternalServerError: "container.Logs.Error",
+	}
+
+	options, err := logsOptions(ctx)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+
+	if options.Follow || common.IsStreamRequest(ctx) {
+		// Fail before switching protocol when the container is unknown
+		if _, err := c.containerService.Inspect(ctx.Request.Context(), ctx.Param("id")); err != nil {
+			status := common.StatusFromError(err)
+			common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+			return
+		}
+
+		stream, err := common.OpenStream(ctx)
+		if err != nil {
+			c.logs.Error().Err(err).Msg("")
+			return
+		}
+		defer stream.Close()
+
+		err = c.containerService.Logs(stream.Context(), ctx.Param("id"), options, func(line models.LogLine) error {
+			return stream.Send("log", line)
+		})
+		if err != nil && stream.Context().Err() == nil {
+			status := common.StatusFromError(err)
+			_ = stream.Send("error", models.KnownError(status, common.MessageType(messageTypes, status), err))
+		}
+		return
+	}
+
+	lines := []models.LogLine{}
+	err = c.containerService.Logs(ctx.Request.Context(), ctx.Param("id"), options, func(line models.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Logs",
+			TotalCount: len(lines),
+			Count:      len(lines),
+			Offset:     1,
+		},
+		Data: lines,
+	}
+
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// logsOptions reads the logs query parameters.
+func logsOptions(ctx *gin.Context) (container.LogsOptions, error) {
+	options := container.LogsOptions{
+		Since: ctx.Query("since"),
+		Until: ctx.Query("until"),
+		Tail:  ctx.Query("tail"),
+	}
+
+	flags := []struct {
+		name  string
+		value *bool
+		def   bool
+	}{
+		{"follow", &options.Follow, false},
+		{"timestamps", &options.Timestamps, false},
+		{"stdout", &options.ShowStdout, true},
+		{"stderr", &options.ShowStderr, true},
+	}
+	for _, flag := range flags {
+		*flag.value = flag.def
+		if value := ctx.Query(flag.name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return options, errors.New(" Invalid " + flag.name + " value. ")
+			}
+			*flag.value = b
+		}
+	}
+
+	// Only a stream may send all the lines
+	stream := options.Follow || common.IsStreamRequest(ctx)
+	if options.Tail == "" {
+		options.Tail = "all"
+		if !stream {
+			options.Tail = strconv.Itoa(defaultLogsTail)
+		}
+	}
+	if options.Tail == "all" && stream {
+		return options, nil
+	}
+
+	n, err := strconv.Atoi(options.Tail)
+	if options.Tail == "all" || (err == nil && n > maxLogsTail && !stream) {
+		return options, errors.New(" The tail value must not exceed " + strconv.Itoa(maxLogsTail) + ", stream the logs for more. ")
+	}
+	if err != nil || n < 0 {
+		return options, errors.New(" Invalid tail value. ")
+	}
+	return options, nil
+}
@@ -0,0 +1,160 @@
+package container
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Resources controller to get the CPU, memory, network and block I/O usage of a container.
+// With stream=true samples are pushed over SSE or WebSocket every interval (default 1s).
+func (c *Container) Resources(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.Resources.Found",
+		BadRequest:          "container.Resources.BadRequest",
+		NotFound:            "container.Resources.NotFound",
+		Conflict:            "container.Resources.Conflict",
+		InternalServerError: "container.Resources.Error",
+	}
+
+	stream, _ := strconv.ParseBool(ctx.Query("stream"))
+	if !stream {
+		resources, err := c.containerService.Resources(ctx.Request.Context(), ctx.Param("id"))
+		if err != nil {
+			status := common.StatusFromError(err)
+			common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+			return
+		}
+
+		response := &models.WSResponse{
+			Meta: models.MetaResponse{
+				ObjectName: "Resources",
+				TotalCount: 1,
+				Count:      1,
+				Offset:     1,
+			},
+			Data: resources,
+		}
+		common.SendResponse(ctx, http.StatusOK, response)
+		return
+	}
+
+	interval, err := parseInterval(ctx.DefaultQuery("interval", "1s"))
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+
+	// Fail before switching protocol when the container is unknown
+	if _, err := c.containerService.Inspect(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	s, err := common.OpenStream(ctx)
+	if err != nil {
+		c.logs.Error().Err(err).Msg("")
+		return
+	}
+	defer s.Close()
+
+	err = c.containerService.StreamResources(s.Context(), ctx.Param("id"), interval, func(resources models.Resources) error {
+		return s.Send("resources", resources)
+	})
+	if err != nil && s.Context().Err() == nil {
+		status := common.StatusFromError(err)
+		_ = s.Send("error", models.KnownError(status, common.MessageType(messageTypes, status), err))
+	}
+}
+
+// Stats controller to get the current resources of every running container
+func (c *Container) Stats(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.Stats.Found",
+		InternalServerError: "container.Stats.Error",
+	}
+
+	resources, err := c.containerService.AllResources(ctx.Request.Context(), middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Resources",
+			TotalCount: len(resources),
+			Count:      len(resources),
+			Offset:     1,
+		},
+		Data: resources,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// StatsHistory controller to get the samples of a container kept by the stats collector.
+// The optional since parameter ("15m") limits the window.
+func (c *Container) StatsHistory(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "container.StatsHistory.Found",
+		BadRequest:          "container.StatsHistory.BadRequest",
+		NotFound:            "container.StatsHistory.NotFound",
+		Conflict:            "container.StatsHistory.Conflict",
+		InternalServerError: "container.StatsHistory.Error",
+	}
+
+	var since time.Time
+	if value := ctx.Query("since"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			status := http.StatusBadRequest
+			common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Invalid since duration. ")))
+			return
+		}
+		since = time.Now().Add(-d)
+	}
+
+	inspect, err := c.containerService.Inspect(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	history := c.statsCollector.History(inspect.ID, since)
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "ResourcesHistory",
+			TotalCount: len(history),
+			Count:      len(history),
+			Offset:     1,
+		},
+		Data: history,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// parseInterval reads a duration ("5s", "1m") or a number of seconds.
+func parseInterval(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, errAtoi := strconv.Atoi(value)
+		if errAtoi != nil {
+			return 0, errors.New(" Invalid interval. ")
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+	if interval < time.Second {
+		return 0, errors.New(" Interval cannot be lower than 1s. ")
+	}
+	return interval, nil
+}
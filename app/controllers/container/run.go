@@ -0,0 +1,97 @@
+package container
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Create controller to run a container from an image, pulling it when missing.
+// Pull progress is streamed over SSE or WebSocket when the client asks for it.
+func (c *Container) Create(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "container.Create.Done",
+		BadRequest:          "container.Create.BadRequest",
+		NotFound:            "container.Create.NotFound",
+		Forbidden:           "container.Create.Forbidden",
+		Conflict:            "container.Create.Conflict",
+		InternalServerError: "container.Create.Error",
+	}
+
+	var spec models.ContainerSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{
+		"image":   spec.Image,
+		"name":    spec.Name,
+		"command": strings.Join(spec.Command, " "),
+		"env":     middlewares.AuditKeys(spec.Env),
+		"binds":   strings.Join(spec.Volumes, ","),
+		"ports":   strings.Join(spec.Ports, ","),
+	})
+
+	// Containers created by a scoped caller carry the labels of its scope
+	var conflict string
+	if spec.Labels, conflict = middlewares.Scope(ctx).Apply(spec.Labels); conflict != "" {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errors.New(" Label "+conflict+" is out of your scope. ")))
+		return
+	}
+	if err := c.containerService.AuthorizeBinds(ctx.Request.Context(), spec.Volumes, middlewares.Scope(ctx)); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	if common.IsStreamRequest(ctx) {
+		stream, err := common.OpenStream(ctx)
+		if err != nil {
+			c.logs.Error().Err(err).Msg("")
+			return
+		}
+		defer stream.Close()
+
+		created, err := c.containerService.Run(stream.Context(), &spec, func(progress models.PullProgress) error {
+			return stream.Send("pull", progress)
+		})
+		if err != nil {
+			status := common.StatusFromError(err)
+			ctx.Set(middlewares.AuditOutcomeKey, status)
+			_ = stream.Send("error", models.KnownError(status, common.MessageType(messageTypes, status), err))
+			return
+		}
+		ctx.Set(middlewares.AuditOutcomeKey, http.StatusCreated)
+		ctx.Set(middlewares.ContainerKey, created.ID)
+		ctx.Set(middlewares.ContainerNameKey, created.Name)
+		_ = stream.Send("created", created)
+		return
+	}
+
+	created, err := c.containerService.Run(ctx.Request.Context(), &spec, nil)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+	ctx.Set(middlewares.ContainerKey, created.ID)
+	ctx.Set(middlewares.ContainerNameKey, created.Name)
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Docker",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: created,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
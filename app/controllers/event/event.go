@@ -0,0 +1,55 @@
+package event
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Event struct {
+	eventService *services.Event
+	logs         *zerolog.Logger
+}
+
+func New(eventService *services.Event, logs *zerolog.Logger) *Event {
+	return &Event{
+		eventService: eventService,
+		logs:         logs,
+	}
+}
+
+// Get controller to stream the daemon events over SSE, or WebSocket when asked for.
+// Events are filtered with filter=type:container, filter=action:die or filter=label:team=payments.
+func (e *Event) Get(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		BadRequest:          "event.Stream.BadRequest",
+		InternalServerError: "event.Stream.Error",
+	}
+
+	filterClauses := ctx.QueryArray("filter")
+	// Fail before switching protocol when the filters are wrong
+	if err := services.CheckEventFilters(filterClauses); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	stream, err := common.OpenStream(ctx)
+	if err != nil {
+		e.logs.Error().Err(err).Msg("")
+		return
+	}
+	defer stream.Close()
+
+	err = e.eventService.Subscribe(stream.Context(), filterClauses, middlewares.Scope(ctx), func(event models.Event) error {
+		return stream.Send(event.Type, event)
+	})
+	if err != nil && stream.Context().Err() == nil {
+		status := common.StatusFromError(err)
+		_ = stream.Send("error", models.KnownError(status, common.MessageType(messageTypes, status), err))
+	}
+}
@@ -0,0 +1,278 @@
+package image
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/functions"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Image struct {
+	imageService *services.Image
+	logs         *zerolog.Logger
+}
+
+func New(imageService *services.Image, logs *zerolog.Logger) *Image {
+	return &Image{
+		imageService: imageService,
+		logs:         logs,
+	}
+}
+
+// Get controller to get list of images
+func (i *Image) Get(ctx *gin.Context) {
+	var params models.QueryParams
+
+	params.Parse(ctx)
+	messageTypes := &models.MessageTypes{
+		OK:                  "image.Search.Found",
+		BadRequest:          "image.Search.BadRequest",
+		NotFound:            "image.Search.NotFound",
+		InternalServerError: "image.Search.Error",
+	}
+
+	if params.Export != "" && !functions.Contains(common.ExportFormats, params.Export) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Unknown export format. ")))
+		return
+	}
+
+	images, err := i.imageService.List(ctx.Request.Context(), &params)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	// Exports hold the whole result set
+	if params.Export != "" {
+		if err := common.Export(ctx, "images", params.Export, images, params.Columns); err != nil {
+			i.logs.Error().Err(err).Msg("")
+		}
+		return
+	}
+
+	totalCount := len(images)
+	if totalCount == 0 {
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+		return
+	}
+
+	low, high, err := common.Page(&params, totalCount)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
+	}
+
+	sendingImages, err := common.ProjectList(images[low:high], params.Columns)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Images",
+			TotalCount: totalCount,
+			Count:      high - low,
+			Offset:     low + 1,
+		},
+		Data: sendingImages,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// GetOne controller to get the detail of an image by reference, ID or short ID
+func (i *Image) GetOne(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "image.Get.Found",
+		BadRequest:          "image.Get.BadRequest",
+		NotFound:            "image.Get.NotFound",
+		InternalServerError: "image.Get.Error",
+	}
+
+	inspect, err := i.imageService.Inspect(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Image",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: inspect,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// History controller to get the layers of an image
+func (i *Image) History(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "image.History.Found",
+		BadRequest:          "image.History.BadRequest",
+		NotFound:            "image.History.NotFound",
+		InternalServerError: "image.History.Error",
+	}
+
+	history, err := i.imageService.History(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "ImageHistory",
+			TotalCount: len(history),
+			Count:      len(history),
+			Offset:     1,
+		},
+		Data: history,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Pull controller to pull an image.
+// Pull progress is streamed over SSE or WebSocket when the client asks for it.
+func (i *Image) Pull(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "image.Pull.Done",
+		BadRequest:          "image.Pull.BadRequest",
+		NotFound:            "image.Pull.NotFound",
+		InternalServerError: "image.Pull.Error",
+	}
+
+	var spec models.ImagePull
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"image": spec.Image})
+
+	if common.IsStreamRequest(ctx) {
+		stream, err := common.OpenStream(ctx)
+		if err != nil {
+			i.logs.Error().Err(err).Msg("")
+			return
+		}
+		defer stream.Close()
+
+		inspect, err := i.imageService.Pull(stream.Context(), &spec, func(progress models.PullProgress) error {
+			return stream.Send("pull", progress)
+		})
+		if err != nil {
+			status := common.StatusFromError(err)
+			ctx.Set(middlewares.AuditOutcomeKey, status)
+			_ = stream.Send("error", models.KnownError(status, common.MessageType(messageTypes, status), err))
+			return
+		}
+		ctx.Set(middlewares.AuditOutcomeKey, http.StatusCreated)
+		_ = stream.Send("pulled", inspect)
+		return
+	}
+
+	inspect, err := i.imageService.Pull(ctx.Request.Context(), &spec, nil)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Image",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: inspect,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Tag controller to add a tag to an image
+func (i *Image) Tag(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "image.Tag.Done",
+		BadRequest:          "image.Tag.BadRequest",
+		NotFound:            "image.Tag.NotFound",
+		InternalServerError: "image.Tag.Error",
+	}
+
+	var spec models.ImageTag
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"image": ctx.Param("id"), "tag": spec.Tag})
+
+	tagged, err := i.imageService.Tag(ctx.Request.Context(), ctx.Param("id"), &spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "ImageTag",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: tagged,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Remove controller to untag and delete an image.
+// force removes an image used by stopped containers, noprune keeps its untagged parents.
+func (i *Image) Remove(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "image.Remove.Done",
+		BadRequest:          "image.Remove.BadRequest",
+		NotFound:            "image.Remove.NotFound",
+		Conflict:            "image.Remove.Conflict",
+		InternalServerError: "image.Remove.Error",
+	}
+
+	force, _ := strconv.ParseBool(ctx.Query("force"))
+	noprune, _ := strconv.ParseBool(ctx.Query("noprune"))
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"image": ctx.Param("id")})
+
+	deleted, err := i.imageService.Remove(ctx.Request.Context(), ctx.Param("id"), force, noprune)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "ImageDeleted",
+			TotalCount: len(deleted),
+			Count:      len(deleted),
+			Offset:     1,
+		},
+		Data: deleted,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
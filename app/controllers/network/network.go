@@ -0,0 +1,244 @@
+package network
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/functions"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Network struct {
+	networkService *services.Network
+	logs           *zerolog.Logger
+}
+
+func New(networkService *services.Network, logs *zerolog.Logger) *Network {
+	return &Network{
+		networkService: networkService,
+		logs:           logs,
+	}
+}
+
+// Get controller to get list of networks
+func (n *Network) Get(ctx *gin.Context) {
+	var params models.QueryParams
+
+	params.Parse(ctx)
+	messageTypes := &models.MessageTypes{
+		OK:                  "network.Search.Found",
+		BadRequest:          "network.Search.BadRequest",
+		NotFound:            "network.Search.NotFound",
+		InternalServerError: "network.Search.Error",
+	}
+
+	if params.Export != "" && !functions.Contains(common.ExportFormats, params.Export) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Unknown export format. ")))
+		return
+	}
+
+	networks, err := n.networkService.List(ctx.Request.Context(), &params, middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	// Exports hold the whole result set
+	if params.Export != "" {
+		if err := common.Export(ctx, "networks", params.Export, networks, params.Columns); err != nil {
+			n.logs.Error().Err(err).Msg("")
+		}
+		return
+	}
+
+	totalCount := len(networks)
+	if totalCount == 0 {
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+		return
+	}
+
+	low, high, err := common.Page(&params, totalCount)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
+	}
+
+	sendingNetworks, err := common.ProjectList(networks[low:high], params.Columns)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Networks",
+			TotalCount: totalCount,
+			Count:      high - low,
+			Offset:     low + 1,
+		},
+		Data: sendingNetworks,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// GetOne controller to get the detail of a network by name, ID or short ID, with its containers
+func (n *Network) GetOne(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "network.Get.Found",
+		BadRequest:          "network.Get.BadRequest",
+		NotFound:            "network.Get.NotFound",
+		InternalServerError: "network.Get.Error",
+	}
+
+	inspect, err := n.networkService.Inspect(ctx.Request.Context(), ctx.Param("id"), middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Network",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: inspect,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Create controller to create a network
+func (n *Network) Create(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "network.Create.Done",
+		BadRequest:          "network.Create.BadRequest",
+		Forbidden:           "network.Create.Forbidden",
+		Conflict:            "network.Create.Conflict",
+		InternalServerError: "network.Create.Error",
+	}
+
+	var spec models.NetworkSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"network": spec.Name})
+
+	// Networks created by a scoped caller carry the labels of its scope
+	var conflict string
+	if spec.Labels, conflict = middlewares.Scope(ctx).Apply(spec.Labels); conflict != "" {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errors.New(" Label "+conflict+" is out of your scope. ")))
+		return
+	}
+
+	created, err := n.networkService.Create(ctx.Request.Context(), &spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Network",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: created,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Remove controller to remove a network without containers
+func (n *Network) Remove(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "network.Remove.Done",
+		BadRequest:          "network.Remove.BadRequest",
+		NotFound:            "network.Remove.NotFound",
+		Forbidden:           "network.Remove.Forbidden",
+		InternalServerError: "network.Remove.Error",
+	}
+
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"network": ctx.Param("id")})
+
+	if err := n.networkService.Remove(ctx.Request.Context(), ctx.Param("id"), middlewares.Scope(ctx)); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, "network removed: "+ctx.Param("id")))
+}
+
+// Connect controller to attach a container to a network, with aliases and a static address
+func (n *Network) Connect(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "network.Connect.Done",
+		BadRequest:          "network.Connect.BadRequest",
+		NotFound:            "network.Connect.NotFound",
+		Forbidden:           "network.Connect.Forbidden",
+		Conflict:            "network.Connect.Conflict",
+		InternalServerError: "network.Connect.Error",
+	}
+
+	var spec models.NetworkConnect
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"network": ctx.Param("id")})
+	ctx.Set(middlewares.ContainerKey, spec.Container)
+
+	if err := n.networkService.Connect(ctx.Request.Context(), ctx.Param("id"), &spec, middlewares.Scope(ctx)); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, "container "+spec.Container+" connected to "+ctx.Param("id")))
+}
+
+// Disconnect controller to detach a container from a network
+func (n *Network) Disconnect(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "network.Disconnect.Done",
+		BadRequest:          "network.Disconnect.BadRequest",
+		NotFound:            "network.Disconnect.NotFound",
+		Forbidden:           "network.Disconnect.Forbidden",
+		Conflict:            "network.Disconnect.Conflict",
+		InternalServerError: "network.Disconnect.Error",
+	}
+
+	var spec models.NetworkDisconnect
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"network": ctx.Param("id")})
+	ctx.Set(middlewares.ContainerKey, spec.Container)
+
+	if err := n.networkService.Disconnect(ctx.Request.Context(), ctx.Param("id"), &spec, middlewares.Scope(ctx)); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, "container "+spec.Container+" disconnected from "+ctx.Param("id")))
+}
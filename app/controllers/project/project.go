@@ -0,0 +1,191 @@
+package project
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/functions"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Project struct {
+	projectService *services.Project
+	logs           *zerolog.Logger
+}
+
+func New(projectService *services.Project, logs *zerolog.Logger) *Project {
+	return &Project{
+		projectService: projectService,
+		logs:           logs,
+	}
+}
+
+// Get controller to get list of compose projects with their services and health
+func (p *Project) Get(ctx *gin.Context) {
+	var params models.QueryParams
+
+	params.Parse(ctx)
+	messageTypes := &models.MessageTypes{
+		OK:                  "project.Search.Found",
+		BadRequest:          "project.Search.BadRequest",
+		NotFound:            "project.Search.NotFound",
+		InternalServerError: "project.Search.Error",
+	}
+
+	if params.Export != "" && !functions.Contains(common.ExportFormats, params.Export) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Unknown export format. ")))
+		return
+	}
+
+	projects, err := p.projectService.List(ctx.Request.Context(), &params, middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	// Exports hold the whole result set
+	if params.Export != "" {
+		if err := common.Export(ctx, "projects", params.Export, projects, params.Columns); err != nil {
+			p.logs.Error().Err(err).Msg("")
+		}
+		return
+	}
+
+	totalCount := len(projects)
+	if totalCount == 0 {
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+		return
+	}
+
+	low, high, err := common.Page(&params, totalCount)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
+	}
+
+	sendingProjects, err := common.ProjectList(projects[low:high], params.Columns)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Projects",
+			TotalCount: totalCount,
+			Count:      high - low,
+			Offset:     low + 1,
+		},
+		Data: sendingProjects,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// GetOne controller to get a compose project by name
+func (p *Project) GetOne(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "project.Get.Found",
+		BadRequest:          "project.Get.BadRequest",
+		NotFound:            "project.Get.NotFound",
+		InternalServerError: "project.Get.Error",
+	}
+
+	project, err := p.projectService.Get(ctx.Request.Context(), ctx.Param("name"), middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Project",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: project,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Start controller to start all the containers of a project
+func (p *Project) Start(ctx *gin.Context) {
+	p.action(ctx, "start")
+}
+
+// Stop controller to stop all the containers of a project, killing them after the optional timeout (seconds)
+func (p *Project) Stop(ctx *gin.Context) {
+	p.action(ctx, "stop")
+}
+
+// Restart controller to restart all the containers of a project
+func (p *Project) Restart(ctx *gin.Context) {
+	p.action(ctx, "restart")
+}
+
+// Down controller to stop and remove all the containers of a project, then its unused networks.
+// Volumes are kept.
+func (p *Project) Down(ctx *gin.Context) {
+	p.action(ctx, "down")
+}
+
+// action runs an action on all the containers of a project and sends the result of each of them.
+// Containers failing do not fail the request, the result counts them.
+func (p *Project) action(ctx *gin.Context, action string) {
+	messageTypes := actionMessageTypes(action)
+	name := ctx.Param("name")
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"project": name})
+
+	var timeout *int
+	if value := ctx.Query("timeout"); value != "" && action != "start" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < -1 {
+			status := http.StatusBadRequest
+			common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Invalid timeout. ")))
+			return
+		}
+		timeout = &seconds
+	}
+
+	result, err := p.projectService.Action(ctx.Request.Context(), name, action, timeout, middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "ProjectAction",
+			TotalCount: len(result.Containers),
+			Count:      len(result.Containers),
+			Offset:     1,
+		},
+		Data: result,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+func actionMessageTypes(action string) *models.MessageTypes {
+	// "start" is sent as "project.Start.Done"
+	name := strings.ToUpper(action[:1]) + action[1:]
+	return &models.MessageTypes{
+		OK:                  "project." + name + ".Done",
+		BadRequest:          "project." + name + ".BadRequest",
+		NotFound:            "project." + name + ".NotFound",
+		InternalServerError: "project." + name + ".Error",
+	}
+}
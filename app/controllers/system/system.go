@@ -0,0 +1,82 @@
+package system
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type System struct {
+	systemService *services.System
+	logs          *zerolog.Logger
+}
+
+func New(systemService *services.System, logs *zerolog.Logger) *System {
+	return &System{
+		systemService: systemService,
+		logs:          logs,
+	}
+}
+
+// Prune controller to remove the unused containers, images, networks, volumes and build cache.
+// With dry_run=true nothing is removed, the report tells what would be.
+func (s *System) Prune(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "system.Prune.Done",
+		BadRequest:          "system.Prune.BadRequest",
+		InternalServerError: "system.Prune.Error",
+	}
+
+	dryRun := false
+	if value := ctx.Query("dry_run"); value != "" {
+		var err error
+		// A mistyped dry run must not remove anything
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			status := http.StatusBadRequest
+			common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" dry_run must be true or false. ")))
+			return
+		}
+	}
+
+	var spec models.PruneSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{
+		"containers":  strconv.FormatBool(spec.Containers),
+		"images":      spec.Images,
+		"networks":    strconv.FormatBool(spec.Networks),
+		"volumes":     spec.Volumes,
+		"build_cache": strconv.FormatBool(spec.BuildCache),
+		"until":       spec.Until,
+		"labels":      strings.Join(spec.Labels, ","),
+	})
+
+	report, err := s.systemService.Prune(ctx.Request.Context(), &spec, dryRun)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Prune",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: report,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
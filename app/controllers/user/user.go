@@ -0,0 +1,249 @@
+package user
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type User struct {
+	userService *services.User
+	logs        *zerolog.Logger
+}
+
+func New(userService *services.User, logs *zerolog.Logger) *User {
+	return &User{
+		userService: userService,
+		logs:        logs,
+	}
+}
+
+// Login controller to exchange a username and a password for a bearer token
+func (u *User) Login(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "auth.Login.Done",
+		BadRequest:          "auth.Login.BadRequest",
+		Unauthorized:        "auth.Login.Unauthorized",
+		InternalServerError: "auth.Login.Error",
+	}
+
+	var credentials models.Credentials
+	if err := ctx.ShouldBindJSON(&credentials); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+
+	token, err := u.userService.Login(credentials)
+	if err != nil {
+		status := common.StatusFromError(err)
+		if status == http.StatusUnauthorized {
+			u.logs.Warn().Str("username", credentials.Username).Str("ip", ctx.ClientIP()).Msg("Login failed")
+		}
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, token)
+}
+
+// Get controller to get the list of users, within the scope of the caller
+func (u *User) Get(ctx *gin.Context) {
+	scope := middlewares.Scope(ctx)
+	users := []models.User{}
+	for _, user := range u.userService.List() {
+		if user.Scope.Within(scope) {
+			users = append(users, user)
+		}
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Users",
+			TotalCount: len(users),
+			Count:      len(users),
+			Offset:     1,
+		},
+		Data: users,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Create controller to add a user
+func (u *User) Create(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "user.Create.Done",
+		BadRequest:          "user.Create.BadRequest",
+		Forbidden:           "user.Create.Forbidden",
+		Conflict:            "user.Create.Conflict",
+		InternalServerError: "user.Create.Error",
+	}
+
+	var spec models.UserSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"username": spec.Username, "role": string(spec.Role), "scope": middlewares.AuditLabels(spec.Scope)})
+
+	if !spec.Scope.Within(middlewares.Scope(ctx)) {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errScope))
+		return
+	}
+
+	created, err := u.userService.Create(spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "User",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: created,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Update controller to change the role and the scope of a user
+func (u *User) Update(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "user.Update.Done",
+		BadRequest:          "user.Update.BadRequest",
+		Forbidden:           "user.Update.Forbidden",
+		NotFound:            "user.Update.NotFound",
+		InternalServerError: "user.Update.Error",
+	}
+
+	var update models.UserUpdate
+	if err := ctx.ShouldBindJSON(&update); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"role": string(update.Role), "scope": middlewares.AuditLabels(update.Scope)})
+
+	if !u.manages(ctx, messageTypes) {
+		return
+	}
+	if !update.Scope.Within(middlewares.Scope(ctx)) {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errScope))
+		return
+	}
+
+	updated, err := u.userService.Update(ctx.Param("username"), update)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "User",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: updated,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// ChangePassword controller to change the password of the authenticated user
+func (u *User) ChangePassword(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "user.Password.Done",
+		BadRequest:          "user.Password.BadRequest",
+		Forbidden:           "user.Password.Forbidden",
+		NotFound:            "user.Password.NotFound",
+		InternalServerError: "user.Password.Error",
+	}
+
+	username := ctx.Param("username")
+	if username != middlewares.Subject(ctx) {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errors.New(" Only your own password can be changed. ")))
+		return
+	}
+
+	var change models.PasswordChange
+	if err := ctx.ShouldBindJSON(&change); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+
+	if err := u.userService.ChangePassword(username, change); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, " Password changed. "))
+}
+
+// ResetPassword controller to replace the password of a user by a generated one
+func (u *User) ResetPassword(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "user.Reset.Done",
+		Forbidden:           "user.Reset.Forbidden",
+		NotFound:            "user.Reset.NotFound",
+		InternalServerError: "user.Reset.Error",
+	}
+
+	if !u.manages(ctx, messageTypes) {
+		return
+	}
+
+	reset, err := u.userService.ResetPassword(ctx.Param("username"))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "PasswordReset",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: reset,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+var errScope = errors.New(" The scope cannot be wider than yours. ")
+
+// manages checks that the user of the username parameter is within the scope of the caller,
+// so that a scoped admin cannot take over a wider account.
+func (u *User) manages(ctx *gin.Context, messageTypes *models.MessageTypes) bool {
+	target, err := u.userService.Get(ctx.Param("username"))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return false
+	}
+	if !target.Scope.Within(middlewares.Scope(ctx)) {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errScope))
+		return false
+	}
+	return true
+}
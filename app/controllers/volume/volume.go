@@ -0,0 +1,190 @@
+package volume
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/functions"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Volume struct {
+	volumeService *services.Volume
+	logs          *zerolog.Logger
+}
+
+func New(volumeService *services.Volume, logs *zerolog.Logger) *Volume {
+	return &Volume{
+		volumeService: volumeService,
+		logs:          logs,
+	}
+}
+
+// Get controller to get list of volumes with their size and the containers mounting them
+func (v *Volume) Get(ctx *gin.Context) {
+	var params models.QueryParams
+
+	params.Parse(ctx)
+	messageTypes := &models.MessageTypes{
+		OK:                  "volume.Search.Found",
+		BadRequest:          "volume.Search.BadRequest",
+		NotFound:            "volume.Search.NotFound",
+		InternalServerError: "volume.Search.Error",
+	}
+
+	if params.Export != "" && !functions.Contains(common.ExportFormats, params.Export) {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, errors.New(" Unknown export format. ")))
+		return
+	}
+
+	volumes, err := v.volumeService.List(ctx.Request.Context(), &params, middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	// Exports hold the whole result set
+	if params.Export != "" {
+		if err := common.Export(ctx, "volumes", params.Export, volumes, params.Columns); err != nil {
+			v.logs.Error().Err(err).Msg("")
+		}
+		return
+	}
+
+	totalCount := len(volumes)
+	if totalCount == 0 {
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, errors.New(" Data not found. ")))
+		return
+	}
+
+	low, high, err := common.Page(&params, totalCount)
+	if err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return
+	}
+
+	sendingVolumes, err := common.ProjectList(volumes[low:high], params.Columns)
+	if err != nil {
+		status := http.StatusInternalServerError
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.InternalServerError, err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Volumes",
+			TotalCount: totalCount,
+			Count:      high - low,
+			Offset:     low + 1,
+		},
+		Data: sendingVolumes,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// GetOne controller to get the detail of a volume by name, with its size and the containers mounting it
+func (v *Volume) GetOne(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "volume.Get.Found",
+		BadRequest:          "volume.Get.BadRequest",
+		NotFound:            "volume.Get.NotFound",
+		InternalServerError: "volume.Get.Error",
+	}
+
+	usage, err := v.volumeService.Inspect(ctx.Request.Context(), ctx.Param("id"), middlewares.Scope(ctx))
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Volume",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: usage,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Create controller to create a volume
+func (v *Volume) Create(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "volume.Create.Done",
+		BadRequest:          "volume.Create.BadRequest",
+		NotFound:            "volume.Create.NotFound",
+		Forbidden:           "volume.Create.Forbidden",
+		Conflict:            "volume.Create.Conflict",
+		InternalServerError: "volume.Create.Error",
+	}
+
+	var spec models.VolumeSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"volume": spec.Name})
+
+	// Volumes created by a scoped caller carry the labels of its scope
+	var conflict string
+	if spec.Labels, conflict = middlewares.Scope(ctx).Apply(spec.Labels); conflict != "" {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errors.New(" Label "+conflict+" is out of your scope. ")))
+		return
+	}
+
+	created, err := v.volumeService.Create(ctx.Request.Context(), &spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Volume",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: created,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Remove controller to remove a volume mounted by no container.
+// force succeeds when the volume does not exist.
+func (v *Volume) Remove(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "volume.Remove.Done",
+		BadRequest:          "volume.Remove.BadRequest",
+		NotFound:            "volume.Remove.NotFound",
+		Conflict:            "volume.Remove.Conflict",
+		InternalServerError: "volume.Remove.Error",
+	}
+
+	force, _ := strconv.ParseBool(ctx.Query("force"))
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"volume": ctx.Param("id")})
+
+	if err := v.volumeService.Remove(ctx.Request.Context(), ctx.Param("id"), force, middlewares.Scope(ctx)); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, "volume removed: "+ctx.Param("id")))
+}
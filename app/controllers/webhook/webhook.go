@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type Webhook struct {
+	webhookService *services.Webhook
+	logs           *zerolog.Logger
+}
+
+func New(webhookService *services.Webhook, logs *zerolog.Logger) *Webhook {
+	return &Webhook{
+		webhookService: webhookService,
+		logs:           logs,
+	}
+}
+
+// Get controller to get the list of webhooks, within the scope of the caller
+func (w *Webhook) Get(ctx *gin.Context) {
+	scope := middlewares.Scope(ctx)
+	hooks := []models.Webhook{}
+	for _, hook := range w.webhookService.List() {
+		if hook.Scope.Within(scope) {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Webhooks",
+			TotalCount: len(hooks),
+			Count:      len(hooks),
+			Offset:     1,
+		},
+		Data: hooks,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// Create controller to register a webhook, its secret is only returned in this response
+func (w *Webhook) Create(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		Created:             "webhook.Create.Done",
+		BadRequest:          "webhook.Create.BadRequest",
+		Forbidden:           "webhook.Create.Forbidden",
+		InternalServerError: "webhook.Create.Error",
+	}
+
+	var spec models.WebhookSpec
+	if err := ctx.ShouldBindJSON(&spec); err != nil {
+		status := http.StatusBadRequest
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.BadRequest, err))
+		return
+	}
+	// The path of a webhook URL often holds a secret, only its host is kept
+	host := spec.URL
+	if u, err := url.Parse(spec.URL); err == nil {
+		host = u.Host
+	}
+	ctx.Set(middlewares.AuditParametersKey, map[string]string{"host": host, "events": strings.Join(spec.Events, ","), "scope": middlewares.AuditLabels(spec.Scope)})
+
+	if !spec.Scope.Within(middlewares.Scope(ctx)) {
+		status := http.StatusForbidden
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.Forbidden, errors.New(" The scope cannot be wider than yours. ")))
+		return
+	}
+
+	created, err := w.webhookService.Create(middlewares.Subject(ctx), spec)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "Webhook",
+			TotalCount: 1,
+			Count:      1,
+			Offset:     1,
+		},
+		Data: created,
+	}
+	common.SendResponse(ctx, http.StatusCreated, response)
+}
+
+// Delete controller to remove a webhook
+func (w *Webhook) Delete(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "webhook.Delete.Done",
+		NotFound:            "webhook.Delete.NotFound",
+		InternalServerError: "webhook.Delete.Error",
+	}
+
+	hook, ok := w.webhook(ctx, messageTypes)
+	if !ok {
+		return
+	}
+	if err := w.webhookService.Delete(hook.ID); err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	common.SendResponse(ctx, http.StatusOK, models.Success(http.StatusOK, messageTypes.OK, "webhook deleted: "+hook.ID))
+}
+
+// Deliveries controller to get the last deliveries of a webhook
+func (w *Webhook) Deliveries(ctx *gin.Context) {
+	messageTypes := &models.MessageTypes{
+		OK:                  "webhook.Deliveries.Found",
+		NotFound:            "webhook.Deliveries.NotFound",
+		InternalServerError: "webhook.Deliveries.Error",
+	}
+
+	hook, ok := w.webhook(ctx, messageTypes)
+	if !ok {
+		return
+	}
+	deliveries, err := w.webhookService.Deliveries(hook.ID)
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return
+	}
+
+	response := &models.WSResponse{
+		Meta: models.MetaResponse{
+			ObjectName: "WebhookDeliveries",
+			TotalCount: len(deliveries),
+			Count:      len(deliveries),
+			Offset:     1,
+		},
+		Data: deliveries,
+	}
+	common.SendResponse(ctx, http.StatusOK, response)
+}
+
+// webhook returns the webhook of the id parameter. Webhooks out of the scope
+// of the caller are reported as not found.
+func (w *Webhook) webhook(ctx *gin.Context, messageTypes *models.MessageTypes) (models.Webhook, bool) {
+	hook, err := w.webhookService.Get(ctx.Param("id"))
+	if err == nil && !hook.Scope.Within(middlewares.Scope(ctx)) {
+		err = errors.New(" Webhook " + ctx.Param("id") + " not found. ")
+		status := http.StatusNotFound
+		common.SendResponse(ctx, status, models.KnownError(status, messageTypes.NotFound, err))
+		return models.Webhook{}, false
+	}
+	if err != nil {
+		status := common.StatusFromError(err)
+		common.SendResponse(ctx, status, models.KnownError(status, common.MessageType(messageTypes, status), err))
+		return models.Webhook{}, false
+	}
+	return hook, true
+}
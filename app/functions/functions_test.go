@@ -47,3 +47,80 @@ func TestEncrypt_Decrypt(t *testing.T) {
 	}
 
 }
+
+func TestGetPath(t *testing.T) {
+	obj := map[string]interface{}{
+		"Id":         "abc",
+		"HostConfig": map[string]interface{}{"NetworkMode": "bridge"},
+	}
+	if v, ok := GetPath(obj, "hostconfig.networkMode"); !ok || v != "bridge" {
+		t.Error("nested path not found")
+	}
+	if _, ok := GetPath(obj, "Id.Name"); ok {
+		t.Error("path through a string should not be found")
+	}
+
+	SetPath(obj, "Labels.team", "payments")
+	if v, _ := GetPath(obj, "Labels.team"); v != "payments" {
+		t.Error("path not set")
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	if CompareValues(float64(2), float64(10)) >= 0 {
+		t.Error("numbers should be compared as numbers")
+	}
+	if CompareValues("Exited", "running") >= 0 {
+		t.Error("strings should be compared case insensitively")
+	}
+	if CompareValues(nil, "a") >= 0 {
+		t.Error("nil should be first")
+	}
+	if CompareValues([]interface{}{"/b"}, []interface{}{"/a", "/c"}) <= 0 {
+		t.Error("arrays should be compared on their first element")
+	}
+	if ValueString(float64(1712345678)) != "1712345678" || ValueString([]interface{}{"a", "b"}) != "a,b" {
+		t.Error("unexpected value string")
+	}
+}
+
+func TestProject(t *testing.T) {
+	obj := map[string]interface{}{
+		"Id":         "abc",
+		"Names":      []interface{}{"/nginx"},
+		"HostConfig": map[string]interface{}{"NetworkMode": "bridge", "Annotations": nil},
+	}
+	projected := Project(obj, []string{"id", "hostconfig.networkmode", "Unknown"})
+	expected := map[string]interface{}{
+		"Id":         "abc",
+		"HostConfig": map[string]interface{}{"NetworkMode": "bridge"},
+	}
+	if !reflect.DeepEqual(projected, expected) {
+		t.Errorf("unexpected projection %v", projected)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	obj := map[string]interface{}{
+		"Id":         "abc",
+		"HostConfig": map[string]interface{}{"NetworkMode": "bridge"},
+		"Labels":     map[string]interface{}{},
+	}
+	expected := map[string]interface{}{
+		"Id":                     "abc",
+		"HostConfig.NetworkMode": "bridge",
+		"Labels":                 map[string]interface{}{},
+	}
+	if flat := Flatten(obj); !reflect.DeepEqual(flat, expected) {
+		t.Errorf("unexpected flat object %v", flat)
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	for i := 0; i < 2000; i++ {
+		password := GeneratePassword(16, 2, 2, 2)
+		if len(password) != 16 || !IsPasswordValid(password, 12) {
+			t.Fatalf("generated password %q is not valid", password)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package functions
 
 import (
-	"math/rand"
-	"strings"
+	"crypto/rand"
+	"math/big"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -21,44 +21,43 @@ func CheckPassword(password string, hash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-// GeneratePassword ...
+// GeneratePassword returns a random password of passwordLength characters holding at least
+// minSpecialChar special characters, minNum digits, minUpperCase upper case letters and
+// a lower case letter.
 func GeneratePassword(passwordLength, minSpecialChar, minNum, minUpperCase int) string {
 	var (
-		lowerCharSet   = "abcdedfghijklmnopqrst"
+		lowerCharSet   = "abcdefghijklmnopqrstuvwxyz"
 		upperCharSet   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 		specialCharSet = "!@#$%&*"
 		numberSet      = "0123456789"
 		allCharSet     = lowerCharSet + upperCharSet + specialCharSet + numberSet
 	)
 
-	var password strings.Builder
-
-	//Set special character
-	for i := 0; i < minSpecialChar; i++ {
-		random := rand.Intn(len(specialCharSet))
-		password.WriteString(string(specialCharSet[random]))
+	password := make([]byte, 0, passwordLength)
+	pick := func(set string, count int) {
+		for i := 0; i < count; i++ {
+			password = append(password, set[randomInt(len(set))])
+		}
 	}
-
-	//Set numeric
-	for i := 0; i < minNum; i++ {
-		random := rand.Intn(len(numberSet))
-		password.WriteString(string(numberSet[random]))
-	}
-
-	//Set uppercase
-	for i := 0; i < minUpperCase; i++ {
-		random := rand.Intn(len(upperCharSet))
-		password.WriteString(string(upperCharSet[random]))
+	pick(specialCharSet, minSpecialChar)
+	pick(numberSet, minNum)
+	pick(upperCharSet, minUpperCase)
+	pick(lowerCharSet, 1)
+	pick(allCharSet, passwordLength-len(password))
+
+	// Fisher-Yates shuffle
+	for i := len(password) - 1; i > 0; i-- {
+		j := randomInt(i + 1)
+		password[i], password[j] = password[j], password[i]
 	}
+	return string(password)
+}
 
-	remainingLength := passwordLength - minSpecialChar - minNum - minUpperCase
-	for i := 0; i < remainingLength; i++ {
-		random := rand.Intn(len(allCharSet))
-		password.WriteString(string(allCharSet[random]))
+// randomInt returns a uniform random number in [0, n) from crypto/rand.
+func randomInt(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(err)
 	}
-	inRune := []rune(password.String())
-	rand.Shuffle(len(inRune), func(i, j int) {
-		inRune[i], inRune[j] = inRune[j], inRune[i]
-	})
-	return string(inRune)
+	return int(v.Int64())
 }
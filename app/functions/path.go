@@ -0,0 +1,174 @@
+package functions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToMap converts a struct to its JSON object form.
+func ToMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+// GetPath returns the value at a dot path ("HostConfig.NetworkMode") of a JSON object.
+// Keys are matched case insensitively.
+func GetPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		k, ok := findKey(m, key)
+		if !ok {
+			return nil, false
+		}
+		current = m[k]
+	}
+	return current, true
+}
+
+// SetPath sets the value at a dot path of a JSON object, creating the intermediate objects.
+func SetPath(obj map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			obj[key] = next
+		}
+		obj = next
+	}
+	obj[keys[len(keys)-1]] = value
+}
+
+// CompareValues orders two JSON values: nil first, then numbers, booleans and strings.
+// Arrays are compared on their first element.
+func CompareValues(a, b interface{}) int {
+	a, b = firstValue(a), firstValue(b)
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	fa, aIsNumber := a.(float64)
+	fb, bIsNumber := b.(float64)
+	if aIsNumber && bIsNumber {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(ValueString(a)), strings.ToLower(ValueString(b)))
+}
+
+// ValueString formats a JSON value for display, arrays are joined with commas.
+func ValueString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%f", value), "0"), ".")
+	case []interface{}:
+		parts := make([]string, len(value))
+		for i, item := range value {
+			parts[i] = ValueString(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		b, _ := json.Marshal(value)
+		return string(b)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func firstValue(v interface{}) interface{} {
+	if values, ok := v.([]interface{}); ok {
+		if len(values) == 0 {
+			return nil
+		}
+		return values[0]
+	}
+	return v
+}
+
+// Project keeps only the given dot paths of a JSON object, with the casing of the object keys.
+// Unknown paths are ignored.
+func Project(obj map[string]interface{}, paths []string) map[string]interface{} {
+	projected := make(map[string]interface{})
+	for _, path := range paths {
+		var current interface{} = obj
+		canonical := make([]string, 0)
+		found := true
+		for _, key := range strings.Split(path, ".") {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			k, ok := findKey(m, key)
+			if !ok {
+				found = false
+				break
+			}
+			canonical = append(canonical, k)
+			current = m[k]
+		}
+		if found {
+			SetPath(projected, strings.Join(canonical, "."), current)
+		}
+	}
+	return projected
+}
+
+// findKey returns the key of m matching key case insensitively, the exact key first.
+func findKey(m map[string]interface{}, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// Flatten returns the leaves of a JSON object keyed by their dot path.
+func Flatten(obj map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	flatten(flat, "", obj)
+	return flat
+}
+
+func flatten(flat map[string]interface{}, prefix string, obj map[string]interface{}) {
+	for k, v := range obj {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			flatten(flat, path, m)
+			continue
+		}
+		flat[path] = v
+	}
+}
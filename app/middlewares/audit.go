@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Keys set by the handlers for the audit log
+const (
+	// ContainerKey and ContainerNameKey name the container a request acts on
+	ContainerKey     = "container"
+	ContainerNameKey = "containerName"
+	// AuditKey records a GET request, a WebSocket session for instance
+	AuditKey = "audit"
+	// AuditParametersKey adds parameters to the entry, a map[string]string
+	AuditParametersKey = "auditParameters"
+	// AuditOutcomeKey holds the status of an operation streamed after its 200 header, an int
+	AuditOutcomeKey = "auditOutcome"
+)
+
+// Audit records the state-changing requests, once handled, in the audit log.
+func Audit(audit *services.Audit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if !c.GetBool(AuditKey) {
+				return
+			}
+		}
+
+		entry := models.AuditEntry{
+			Time:          start.UTC(),
+			Actor:         Subject(c),
+			Role:          Role(c),
+			Scope:         Scope(c),
+			SourceIP:      c.ClientIP(),
+			Method:        c.Request.Method,
+			Route:         c.FullPath(),
+			Path:          c.Request.URL.Path,
+			Container:     c.GetString(ContainerKey),
+			ContainerName: c.GetString(ContainerNameKey),
+			Status:        c.Writer.Status(),
+			Outcome:       "success",
+			DurationMs:    float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if status := c.GetInt(AuditOutcomeKey); status != 0 {
+			entry.Status = status
+		}
+		if entry.Status >= http.StatusBadRequest {
+			entry.Outcome = "failure"
+		}
+		for key, values := range c.Request.URL.Query() {
+			// Never keep credentials
+			if key == "access_token" || len(values) == 0 {
+				continue
+			}
+			if entry.Parameters == nil {
+				entry.Parameters = make(map[string]string)
+			}
+			entry.Parameters[key] = values[0]
+		}
+		for key, value := range c.GetStringMapString(AuditParametersKey) {
+			// Handlers set every field of the body, the omitted ones are empty
+			if value == "" {
+				continue
+			}
+			if entry.Parameters == nil {
+				entry.Parameters = make(map[string]string)
+			}
+			entry.Parameters[key] = value
+		}
+		audit.Record(entry)
+	}
+}
+
+// AuditKeys lists the keys of a map for the audit parameters, its values may be secrets.
+func AuditKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// AuditLabels lists the key=value pairs of a map for the audit parameters.
+func AuditLabels(m map[string]string) string {
+	labels := make([]string, 0, len(m))
+	for key, value := range m {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"adminDocker/app/controllers/common"
+	"adminDocker/app/models"
+	"adminDocker/app/services"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Keys of the authenticated caller in the gin context
+const (
+	SubjectKey = "subject"
+	ClaimsKey  = "claims"
+)
+
+// APIKeyHeader is the header holding the API keys of the automation clients.
+const APIKeyHeader = "X-API-Key"
+
+// Auth rejects the requests without a valid bearer token or API key and stores
+// the subject and the claims of the caller in the gin context.
+// The tokens of a user issued before a password change or reset are rejected.
+func Auth(tokens *services.Tokens, users *services.User, apiKeys *services.APIKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			claims, err := apiKeys.Authenticate(key)
+			if err != nil {
+				messageType := "auth.APIKey.Invalid"
+				if errors.Is(err, services.ErrAPIKeyExpired) {
+					messageType = "auth.APIKey.Expired"
+				}
+				abort(c, messageType, err)
+				return
+			}
+			c.Set(SubjectKey, claims.Subject)
+			c.Set(ClaimsKey, claims)
+			c.Next()
+			return
+		}
+
+		token := bearerToken(c)
+		if token == "" {
+			abort(c, "auth.Token.Missing", errors.New(" Missing bearer token. "))
+			return
+		}
+
+		claims, err := tokens.Verify(token)
+		if err != nil {
+			messageType := "auth.Token.Invalid"
+			if errors.Is(err, services.ErrTokenExpired) {
+				messageType = "auth.Token.Expired"
+			}
+			abort(c, messageType, err)
+			return
+		}
+		if users.Revoked(claims) {
+			abort(c, "auth.Token.Revoked", errors.New(" The password has changed since this token was issued, log in again. "))
+			return
+		}
+
+		// A reset password must be changed before anything else, with a new login after
+		if claims.MustChangePassword && !isOwnPasswordChange(c, claims.Subject) {
+			status := http.StatusForbidden
+			c.AbortWithStatusJSON(status, models.KnownError(status, "auth.Password.MustChange", errors.New(" Your password has been reset, change it then log in again. ")))
+			return
+		}
+
+		c.Set(SubjectKey, claims.Subject)
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// isOwnPasswordChange tells if a request changes the password of subject.
+func isOwnPasswordChange(c *gin.Context, subject string) bool {
+	return c.Request.Method == http.MethodPut && strings.HasSuffix(c.FullPath(), "/users/:username/password") && c.Param("username") == subject
+}
+
+// Subject returns the subject of the authenticated caller.
+func Subject(c *gin.Context) string {
+	return c.GetString(SubjectKey)
+}
+
+// Claims returns the claims of the authenticated caller, nil when not authenticated.
+func Claims(c *gin.Context) *models.Claims {
+	if claims, ok := c.Get(ClaimsKey); ok {
+		return claims.(*models.Claims)
+	}
+	return nil
+}
+
+// bearerToken reads the token of the Authorization header. Browsers cannot set
+// headers on WebSocket and EventSource requests, streams accept an access_token parameter.
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	if common.IsStreamRequest(c) {
+		return c.Query("access_token")
+	}
+	return ""
+}
+
+func abort(c *gin.Context, messageType string, err error) {
+	status := http.StatusUnauthorized
+	c.Header("WWW-Authenticate", `Bearer realm="adminDocker"`)
+	c.AbortWithStatusJSON(status, models.KnownError(status, messageType, err))
+}
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"adminDocker/app/models"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Require rejects the callers whose role does not grant the access of role.
+func Require(role models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Role(c).Allows(role) {
+			status := http.StatusForbidden
+			c.AbortWithStatusJSON(status, models.KnownError(status, "auth.Role.Forbidden", errors.New(" The "+string(role)+" role is required. ")))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Role returns the role of the authenticated caller, viewer when its token carries none.
+func Role(c *gin.Context) models.Role {
+	claims := Claims(c)
+	if claims == nil {
+		return ""
+	}
+	if claims.Role == "" {
+		return models.RoleViewer
+	}
+	return claims.Role
+}
+
+// Scope returns the container labels the authenticated caller is restricted to.
+func Scope(c *gin.Context) models.Scope {
+	if claims := Claims(c); claims != nil {
+		return claims.Scope
+	}
+	return nil
+}
+
+// Unscoped rejects the callers restricted to a scope, on the resources shared by every scope.
+func Unscoped(c *gin.Context) {
+	if len(Scope(c)) > 0 {
+		status := http.StatusForbidden
+		c.AbortWithStatusJSON(status, models.KnownError(status, "auth.Scope.Forbidden", errors.New(" This resource is shared by all scopes. ")))
+		return
+	}
+	c.Next()
+}
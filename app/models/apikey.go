@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// APIKey is a long-lived key of an automation client, sent in the X-API-Key header.
+// - Prefix : *first characters of the key, to recognise it*
+// - Owner : *user who created the key*
+// - ExpiresAt : *no expiry when empty*
+// - RevokedAt : *set once revoked, the key is refused from then on*
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       Role       `json:"role"`
+	Scope      Scope      `json:"scope,omitempty"`
+	Owner      string     `json:"owner"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeySpec is the body to create an API key, viewer when no role is given.
+// - ExpiresIn : *lifetime of the key, "720h" for instance*
+type APIKeySpec struct {
+	Name      string `json:"name" validate:"required,max=128"`
+	Role      Role   `json:"role"`
+	Scope     Scope  `json:"scope"`
+	ExpiresIn string `json:"expires_in"`
+}
+
+// APIKeyCreated is a new API key with its secret value, only shown once.
+type APIKeyCreated struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// AuditEntry records a state-changing request.
+// - Actor : *subject of the caller, a username or apikey:<id>*
+// - Route : *route pattern, "/v1/dockers/:id/stop"*
+// - Container / ContainerName : *full ID and name of the container acted on, when any*
+// - Parameters : *query parameters of the request, and the main fields of its body*
+// - Outcome : *success or failure, from the response status*
+type AuditEntry struct {
+	ID            string            `json:"id"`
+	Time          time.Time         `json:"time"`
+	Actor         string            `json:"actor"`
+	Role          Role              `json:"role,omitempty"`
+	Scope         Scope             `json:"scope,omitempty"`
+	SourceIP      string            `json:"source_ip"`
+	Method        string            `json:"method"`
+	Route         string            `json:"route"`
+	Path          string            `json:"path"`
+	Container     string            `json:"container,omitempty"`
+	ContainerName string            `json:"container_name,omitempty"`
+	Parameters    map[string]string `json:"parameters,omitempty"`
+	Status        int               `json:"status"`
+	Outcome       string            `json:"outcome"`
+	DurationMs    float64           `json:"duration_ms"`
+}
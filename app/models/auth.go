@@ -0,0 +1,80 @@
+package models
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Role grants access to a set of endpoints, each role including the previous one.
+// - viewer : *lists containers, reads their detail, logs and resources*
+// - operator : *starts, stops, restarts, pauses and kills containers*
+// - admin : *creates containers and manages the users*
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Valid tells if the role is known.
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Allows tells if the role grants the access of required.
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// Scope restricts an identity to the containers carrying all its labels,
+// {"team": "payments"} for instance. An empty scope gives access to every container.
+type Scope map[string]string
+
+// Matches tells if labels hold all the labels of the scope.
+func (s Scope) Matches(labels map[string]string) bool {
+	for key, value := range s {
+		if label, ok := labels[key]; !ok || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply adds the labels of the scope to labels, and returns the key of a label
+// holding another value, if any.
+func (s Scope) Apply(labels map[string]string) (map[string]string, string) {
+	for key, value := range s {
+		if label, ok := labels[key]; ok && label != value {
+			return labels, key
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+	}
+	return labels, ""
+}
+
+// Within tells if the scope is at least as narrow as parent.
+func (s Scope) Within(parent Scope) bool {
+	return parent.Matches(s)
+}
+
+// Claims are the claims of the API tokens.
+// - Subject (sub) : *identity of the caller*
+// - Role : *role of the caller, viewer when missing*
+// - Scope : *labels the containers of the caller carry*
+// - MustChangePassword : *the token only allows to change the password, set after a reset*
+// - PasswordVersion : *version of the password of the user, the tokens of a former one are revoked*
+type Claims struct {
+	jwt.RegisteredClaims
+	Role               Role  `json:"role,omitempty"`
+	Scope              Scope `json:"scope,omitempty"`
+	MustChangePassword bool  `json:"must_change_password,omitempty"`
+	PasswordVersion    int   `json:"pwv,omitempty"`
+}
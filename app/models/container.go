@@ -3,3 +3,101 @@ package models
 import "github.com/docker/docker/api/types"
 
 type Container types.Container
+
+// ContainerDetail is the low-level information of a container with the networks it is attached to.
+type ContainerDetail struct {
+	types.ContainerJSON
+	Networks []ContainerNetwork `json:"networks"`
+}
+
+// LogLine is a line of a container output.
+// - Stream : *stdout or stderr*
+// - Timestamp : *RFC3339Nano time set by the daemon, when asked for*
+// - Message : *line content without its trailing new line*
+type LogLine struct {
+	Stream    string `json:"stream"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ContainerSpec describes a container to run.
+// - Ports : *published ports, "8080:80" or "127.0.0.1:8080:80/tcp"*
+// - Volumes : *binds, "volume:/path" or "/host/path:/path:ro", scoped callers only mount the volumes of their scope*
+// - Memory : *memory limit in bytes*
+// - CPUs : *number of CPUs, 0.5 for half a CPU*
+// - Pull : *missing (default), always or never*
+// - Start : *start the container once created, true when omitted*
+type ContainerSpec struct {
+	Image             string            `json:"image" validate:"required"`
+	Name              string            `json:"name" validate:"omitempty,max=128"`
+	Command           []string          `json:"command"`
+	Env               map[string]string `json:"env"`
+	Ports             []string          `json:"ports"`
+	Volumes           []string          `json:"volumes" validate:"dive,contains=:"`
+	Labels            map[string]string `json:"labels"`
+	RestartPolicy     string            `json:"restart_policy" validate:"omitempty,oneof=no always on-failure unless-stopped"`
+	RestartMaxRetries int               `json:"restart_max_retries" validate:"gte=0"`
+	Memory            int64             `json:"memory" validate:"gte=0"`
+	CPUs              float64           `json:"cpus" validate:"gte=0"`
+	Pull              string            `json:"pull" validate:"omitempty,oneof=missing always never"`
+	Start             *bool             `json:"start"`
+}
+
+// ContainerCreated is returned once a container has been created.
+type ContainerCreated struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Image    string   `json:"image"`
+	Pulled   bool     `json:"pulled"`
+	Started  bool     `json:"started"`
+	Warnings []string `json:"warnings"`
+}
+
+// PullProgress is a progress message of an image pull.
+type PullProgress struct {
+	ID       string `json:"id,omitempty"`
+	Status   string `json:"status"`
+	Progress string `json:"progress,omitempty"`
+	Current  int64  `json:"current,omitempty"`
+	Total    int64  `json:"total,omitempty"`
+}
+
+// ExecSpec describes a command to run in a running container.
+// - Timeout : *seconds before a one-shot command is stopped, 30 when omitted*
+// - Tty : *allocate a terminal, stdout and stderr are then merged*
+// - Height / Width : *initial size of the terminal*
+type ExecSpec struct {
+	Cmd     []string          `json:"cmd" validate:"required,min=1"`
+	Env     map[string]string `json:"env"`
+	WorkDir string            `json:"workdir"`
+	User    string            `json:"user"`
+	Timeout int               `json:"timeout" validate:"gte=0,lte=3600"`
+	Tty     bool              `json:"-"`
+	Height  uint              `json:"-"`
+	Width   uint              `json:"-"`
+}
+
+// ExecResult is the outcome of a one-shot command.
+// - ExitCode : *missing when the command was still running at the timeout*
+// - Truncated : *set when an output exceeded the kept size*
+type ExecResult struct {
+	ExecID     string  `json:"exec_id"`
+	ExitCode   *int    `json:"exit_code"`
+	Stdout     string  `json:"stdout"`
+	Stderr     string  `json:"stderr"`
+	TimedOut   bool    `json:"timed_out"`
+	Truncated  bool    `json:"truncated"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// ExecMessage is a message of an interactive exec session over WebSocket.
+// - Type : *stdin and resize from the client, stdout, stderr and exit from the server*
+// - Data : *text of stdin, stdout and stderr messages*
+// - Rows / Cols : *size of the terminal of a resize message*
+type ExecMessage struct {
+	Type     string `json:"type"`
+	Data     string `json:"data,omitempty"`
+	Rows     uint   `json:"rows,omitempty"`
+	Cols     uint   `json:"cols,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+}
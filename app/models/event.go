@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Event is an event of the Docker daemon.
+// - Type : *container, image, network, volume...*
+// - Action : *start, die, pull, health_status: unhealthy...*
+// - ActorID : *ID of the object the event is about*
+// - Attributes : *name, image and labels of a container for instance*
+type Event struct {
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ActorID    string            `json:"actor_id"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Time       time.Time         `json:"time"`
+}
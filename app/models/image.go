@@ -0,0 +1,17 @@
+package models
+
+// ImagePull names an image to pull, tagged latest when untagged.
+type ImagePull struct {
+	Image string `json:"image" validate:"required"`
+}
+
+// ImageTag names the tag to add to an image, latest when untagged.
+type ImageTag struct {
+	Tag string `json:"tag" validate:"required"`
+}
+
+// ImageTagged is returned once an image has been tagged.
+type ImageTagged struct {
+	ID  string `json:"id"`
+	Tag string `json:"tag"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// NetworkSpec describes a network to create.
+// - Driver : *bridge when omitted*
+// - Subnet : *CIDR of the network, allocated by the daemon when omitted*
+// - Gateway : *address of the gateway in the subnet, its first address when omitted*
+// - Internal : *no access to the outside of the network*
+type NetworkSpec struct {
+	Name     string            `json:"name" validate:"required,max=128"`
+	Driver   string            `json:"driver" validate:"omitempty,max=64"`
+	Subnet   string            `json:"subnet" validate:"omitempty,cidr"`
+	Gateway  string            `json:"gateway" validate:"omitempty,ip"`
+	Labels   map[string]string `json:"labels"`
+	Internal bool              `json:"internal"`
+}
+
+// NetworkCreated is returned once a network has been created.
+type NetworkCreated struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// NetworkConnect attaches a container to a network.
+// - Aliases : *extra DNS names of the container on the network*
+// - IPAddress : *static IPv4 address in the subnet, allocated by the daemon when omitted*
+type NetworkConnect struct {
+	Container string   `json:"container" validate:"required"`
+	Aliases   []string `json:"aliases" validate:"dive,hostname_rfc1123"`
+	IPAddress string   `json:"ip_address" validate:"omitempty,ipv4"`
+}
+
+// NetworkDisconnect detaches a container from a network.
+// - Force : *disconnect the container even when it is not running*
+type NetworkDisconnect struct {
+	Container string `json:"container" validate:"required"`
+	Force     bool   `json:"force"`
+}
+
+// ContainerNetwork is a network a container is attached to.
+type ContainerNetwork struct {
+	Name        string   `json:"name"`
+	ID          string   `json:"id"`
+	Driver      string   `json:"driver"`
+	Internal    bool     `json:"internal"`
+	IPAddress   string   `json:"ip_address"`
+	IPPrefixLen int      `json:"ip_prefix_len"`
+	Gateway     string   `json:"gateway"`
+	MacAddress  string   `json:"mac_address"`
+	Aliases     []string `json:"aliases"`
+}
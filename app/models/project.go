@@ -0,0 +1,51 @@
+package models
+
+// Project is a docker compose project: the containers carrying its label, by service.
+// - Status : *running when all its containers run, exited when none does, partial otherwise*
+// - Running : *number of running containers, out of Total*
+type Project struct {
+	Name     string           `json:"name"`
+	Status   string           `json:"status"`
+	Running  int              `json:"running"`
+	Total    int              `json:"total"`
+	Services []ProjectService `json:"services"`
+}
+
+// ProjectService is a service of a compose project with its containers, one per replica.
+type ProjectService struct {
+	Name       string             `json:"name"`
+	Running    int                `json:"running"`
+	Total      int                `json:"total"`
+	Containers []ProjectContainer `json:"containers"`
+}
+
+// ProjectContainer is a container of a compose service.
+type ProjectContainer struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Image  string `json:"image"`
+	State  string `json:"state"`
+	Status string `json:"status"`
+}
+
+// ProjectActionResult is the result of an action run on all the containers of a project.
+// - Succeeded : *containers done or skipped, Failed the others*
+// - Networks : *networks of the project removed by down*
+type ProjectActionResult struct {
+	Project    string                  `json:"project"`
+	Action     string                  `json:"action"`
+	Succeeded  int                     `json:"succeeded"`
+	Failed     int                     `json:"failed"`
+	Containers []ContainerActionResult `json:"containers"`
+	Networks   []string                `json:"networks,omitempty"`
+}
+
+// ContainerActionResult is the result of an action on a container.
+// - Result : *done, skipped when the container already was in the requested state, or failed*
+type ContainerActionResult struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Service string `json:"service"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
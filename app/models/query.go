@@ -28,6 +28,7 @@ type QueryParams struct {
 	SearchClause     []string
 	Collection       string
 	TestDeleted      bool
+	All              bool
 }
 
 // Parse : QueryParams parser
@@ -37,6 +38,8 @@ func (q *QueryParams) Parse(c *gin.Context) {
 	q.Offset, _ = strconv.Atoi(c.Query("offset"))
 	q.View = c.Query("view")
 	q.GroupBy = c.Query("col")
+	q.All, _ = strconv.ParseBool(c.Query("all"))
+	q.Export = strings.ToLower(c.Query("export"))
 
 	q.Path = c.Request.URL.Path
 
@@ -53,6 +56,14 @@ func (q *QueryParams) Parse(c *gin.Context) {
 		q.SortClause = strings.Split(sort, ",")
 	}
 
+	if columns := c.Query("columns"); len(columns) > 0 {
+		for _, column := range strings.Split(columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				q.Columns = append(q.Columns, column)
+			}
+		}
+	}
+
 	// For POST or PUT requests, reading body JSON
 	if c.Request.Method == http.MethodPost || c.Request.Method == http.MethodPut {
 		var body map[string]interface{}
@@ -74,4 +85,5 @@ func (q *QueryParams) Parse(c *gin.Context) {
 	functions.RemoveDuplicate(&q.FilterClause)
 	functions.RemoveDuplicate(&q.FilterLikeClause)
 	functions.RemoveDuplicate(&q.SearchClause)
+	functions.RemoveDuplicate(&q.Columns)
 }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Resources is a sample of the resources used by a container.
+// - CPUPercent : *share of the host CPUs, 100% per fully used CPU*
+// - MemoryUsage : *memory used without the page cache, in bytes*
+// - MemoryLimit : *memory limit of the container, in bytes*
+// - NetworkRx / NetworkTx : *bytes received and sent on all the networks*
+// - BlockRead / BlockWrite : *bytes read from and written to block devices*
+type Resources struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Read          time.Time `json:"read"`
+	CPUPercent    float64   `json:"cpu_percent"`
+	OnlineCPUs    uint32    `json:"online_cpus"`
+	MemoryUsage   uint64    `json:"memory_usage"`
+	MemoryLimit   uint64    `json:"memory_limit"`
+	MemoryPercent float64   `json:"memory_percent"`
+	NetworkRx     uint64    `json:"network_rx"`
+	NetworkTx     uint64    `json:"network_tx"`
+	BlockRead     uint64    `json:"block_read"`
+	BlockWrite    uint64    `json:"block_write"`
+	PIDs          uint64    `json:"pids"`
+}
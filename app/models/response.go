@@ -13,11 +13,23 @@ type WSResponse struct {
 // - TotalCount : *Total number of records the request can return.
 // - Offset : *Starting position of the list of records returned to the Front.
 // - Count : *Number of records returned to the Front.
+// - GroupBy : *Column the records are grouped on, for aggregations only.
 type MetaResponse struct {
 	ObjectName string `json:"object_name"`
 	TotalCount int    `json:"total_count"`
 	Offset     int    `json:"offSet"`
 	Count      int    `json:"count"`
+	GroupBy    string `json:"group_by,omitempty"`
+}
+
+// Group is a group of records sharing the same value of a column.
+// - Value : *Value of the column.
+// - Count : *Number of records of the group.
+// - IDs : *Records of the group, when asked for.
+type Group struct {
+	Value string   `json:"value"`
+	Count int      `json:"count"`
+	IDs   []string `json:"ids,omitempty"`
 }
 
 // MessageTypes is an array of message types returned to the Front
@@ -0,0 +1,34 @@
+package models
+
+// PruneSpec selects the unused resources a prune removes.
+// - Images : *dangling images only, or all the images without containers*
+// - Volumes : *anonymous volumes only, or all the volumes without containers*
+// - BuildCache : *all the build cache not in use*
+// - Until : *only resources created before, a duration ("24h") or a timestamp*
+// - Labels : *only resources with these labels, "key" or "key=value", "!" excluding them ("!keep")*
+type PruneSpec struct {
+	Containers bool     `json:"containers"`
+	Images     string   `json:"images" validate:"omitempty,oneof=dangling all"`
+	Networks   bool     `json:"networks"`
+	Volumes    string   `json:"volumes" validate:"omitempty,oneof=anonymous all"`
+	BuildCache bool     `json:"build_cache"`
+	Until      string   `json:"until"`
+	Labels     []string `json:"labels" validate:"dive,required"`
+}
+
+// PruneResult lists the resources of a type a prune removed, or would remove.
+type PruneResult struct {
+	Deleted        []string `json:"deleted"`
+	SpaceReclaimed uint64   `json:"space_reclaimed"`
+}
+
+// PruneReport is returned by a prune, only the selected types are set.
+type PruneReport struct {
+	DryRun         bool         `json:"dry_run"`
+	Containers     *PruneResult `json:"containers,omitempty"`
+	Images         *PruneResult `json:"images,omitempty"`
+	Networks       *PruneResult `json:"networks,omitempty"`
+	Volumes        *PruneResult `json:"volumes,omitempty"`
+	BuildCache     *PruneResult `json:"build_cache,omitempty"`
+	SpaceReclaimed uint64       `json:"space_reclaimed"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// User is a local account of the API.
+// - Scope : *labels of the containers the user is restricted to*
+// - MustChangePassword : *set once the password has been reset*
+type User struct {
+	Username           string    `json:"username"`
+	Email              string    `json:"email,omitempty"`
+	Role               Role      `json:"role"`
+	Scope              Scope     `json:"scope,omitempty"`
+	MustChangePassword bool      `json:"must_change_password"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UserSpec is the body to create a user, viewer when no role is given.
+type UserSpec struct {
+	Username string `json:"username" validate:"required"`
+	Email    string `json:"email"`
+	Password string `json:"password" validate:"required"`
+	Role     Role   `json:"role"`
+	Scope    Scope  `json:"scope"`
+}
+
+// UserUpdate is the body to change the role and the scope of a user.
+type UserUpdate struct {
+	Role  Role  `json:"role" validate:"required"`
+	Scope Scope `json:"scope"`
+}
+
+// Credentials is the body of a login.
+type Credentials struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// PasswordChange is the body to change a password.
+type PasswordChange struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// Token is an issued bearer token.
+type Token struct {
+	Token              string    `json:"token"`
+	TokenType          string    `json:"token_type"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	MustChangePassword bool      `json:"must_change_password"`
+}
+
+// PasswordReset holds the generated password of a reset, shown once.
+type PasswordReset struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/docker/docker/api/types/volume"
+
+// VolumeSpec describes a volume to create.
+// - Name : *generated by the daemon when omitted*
+// - Driver : *local when omitted*
+// - DriverOpts : *options of the driver, "type", "device" and "o" for local*
+type VolumeSpec struct {
+	Name       string            `json:"name" validate:"omitempty,max=128"`
+	Driver     string            `json:"driver" validate:"omitempty,max=64"`
+	DriverOpts map[string]string `json:"driver_opts"`
+	Labels     map[string]string `json:"labels"`
+}
+
+// VolumeUsage is a volume with its size and the containers mounting it.
+// - Size : *bytes used by the volume, -1 when the driver does not tell*
+// - Orphan : *mounted by no container, removed by a prune of all the volumes*
+// - Containers : *containers mounting the volume, running or not*
+type VolumeUsage struct {
+	volume.Volume
+	Size       int64             `json:"size"`
+	Orphan     bool              `json:"orphan"`
+	Containers []VolumeContainer `json:"containers"`
+}
+
+// VolumeContainer is a container mounting a volume.
+type VolumeContainer struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Destination string `json:"destination"`
+	RW          bool   `json:"rw"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Webhook receives the container state changes as signed JSON payloads.
+// - Events : *die, restart, unhealthy and oom, all of them when empty*
+// - Scope : *labels the containers must carry, every container when empty*
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events,omitempty"`
+	Scope     Scope     `json:"scope,omitempty"`
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookSpec is the body to register a webhook. A secret is generated when none is given.
+type WebhookSpec struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" validate:"dive,oneof=die restart unhealthy oom"`
+	Scope  Scope    `json:"scope"`
+}
+
+// WebhookCreated is a new webhook with its secret, only shown once.
+type WebhookCreated struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload is the body posted to the webhooks. The X-AdminDocker-Signature
+// header holds "sha256=" and the hex HMAC-SHA256 of the body keyed with the secret.
+type WebhookPayload struct {
+	DeliveryID string    `json:"delivery_id"`
+	WebhookID  string    `json:"webhook_id"`
+	Event      string    `json:"event"`
+	Container  Event     `json:"container"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// WebhookDelivery is an attempt to post an event to a webhook.
+// - Status : *pending, delivered or failed once the retries are exhausted*
+// - StatusCode : *HTTP status of the last attempt, 0 when no response*
+type WebhookDelivery struct {
+	ID          string     `json:"id"`
+	WebhookID   string     `json:"webhook_id"`
+	Event       string     `json:"event"`
+	ContainerID string     `json:"container_id"`
+	Name        string     `json:"name,omitempty"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	StatusCode  int        `json:"status_code,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
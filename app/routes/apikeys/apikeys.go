@@ -0,0 +1,25 @@
+package apikeys
+
+import (
+	controller "adminDocker/app/controllers/apikey"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, apiKeyService *services.APIKey, logs *zerolog.Logger) error {
+
+	apiKeyController := controller.New(apiKeyService, logs)
+
+	apiKeysV1 := v1.Group("/apikeys", middlewares.Require(models.RoleAdmin))
+	{
+		apiKeysV1.GET("", apiKeyController.Get)
+		apiKeysV1.POST("", apiKeyController.Create)
+		apiKeysV1.DELETE("/:id", apiKeyController.Revoke)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package audit
+
+import (
+	controller "adminDocker/app/controllers/audit"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, auditService *services.Audit, logs *zerolog.Logger) error {
+
+	auditController := controller.New(auditService, logs)
+
+	v1.GET("/audit", middlewares.Require(models.RoleAdmin), auditController.Get)
+
+	return nil
+}
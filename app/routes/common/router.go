@@ -3,20 +3,65 @@ package common
 import (
 	"adminDocker/app/controllers/common"
 	"adminDocker/app/models"
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitialiseRouter initialization of web service routes
 func SetupRouter() *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: logFormatter}), gin.Recovery())
+	// Route on the escaped path, so that an encoded "/" stays in its parameter
+	router.UseRawPath = true
 	noRoute(router)
 	useCORS(router)
 	return router
 }
 
+// logFormatter is the format of the gin logger, with the tokens of the query redacted:
+// streams take their bearer token as an access_token parameter.
+func logFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor, methodColor, resetColor = param.StatusCodeColor(), param.MethodColor(), param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		redactQuery(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactQuery hides the value of the access_token parameters of a path.
+func redactQuery(path string) string {
+	path, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		if key, _, _ := strings.Cut(param, "="); key != "" {
+			if name, err := url.QueryUnescape(key); err == nil && name == "access_token" {
+				params[i] = key + "=REDACTED"
+			}
+		}
+	}
+	return path + "?" + strings.Join(params, "&")
+}
+
 func useCORS(r *gin.Engine) {
 	r.Use(func(c *gin.Context) {
 		allowOrigin := os.Getenv("ALLOW_ORIGIN")
@@ -2,26 +2,52 @@ package dockers
 
 import (
 	controller "adminDocker/app/controllers/container"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/server"
 	services "adminDocker/app/services"
+	"context"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 )
 
-func SetupRouter(g *gin.Engine, logs *zerolog.Logger) error {
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
 
-	containerService, err := services.NewServiceContainer(logs)
-	if err != nil {
-		return err
+	containerService := services.NewServiceContainer(backend, logs)
+
+	srv := server.GetServer()
+	statsCollector := services.NewStatsCollector(containerService, srv.StatsInterval, srv.StatsRetention, logs)
+	go statsCollector.Run(context.Background())
+
+	containerController := controller.New(containerService, statsCollector, logs)
+
+	viewer := middlewares.Require(models.RoleViewer)
+	operator := middlewares.Require(models.RoleOperator)
+	admin := middlewares.Require(models.RoleAdmin)
+
+	dockersV1 := v1.Group("/dockers")
+	{
+		dockersV1.GET("", viewer, containerController.Get)
+		dockersV1.POST("", admin, containerController.Create)
+		dockersV1.GET("/stats", viewer, containerController.Stats)
 	}
-	containerController := controller.New(containerService, logs)
 
-	v1 := g.Group("/v1")
+	// Routes on a container are restricted to the scope of the caller
+	dockerV1 := dockersV1.Group("/:id", containerController.InScope)
 	{
-		dockersV1 := v1.Group("/dockers")
-		{
-			dockersV1.GET("", containerController.Get)
-		}
+		dockerV1.GET("", viewer, containerController.GetOne)
+		dockerV1.GET("/logs", viewer, containerController.Logs)
+		dockerV1.GET("/ressources", viewer, containerController.Resources)
+		dockerV1.GET("/stats/history", viewer, containerController.StatsHistory)
+		dockerV1.POST("/start", operator, containerController.Start)
+		dockerV1.POST("/stop", operator, containerController.Stop)
+		dockerV1.POST("/restart", operator, containerController.Restart)
+		dockerV1.POST("/pause", operator, containerController.Pause)
+		dockerV1.POST("/unpause", operator, containerController.Unpause)
+		dockerV1.POST("/kill", operator, containerController.Kill)
+		dockerV1.GET("/exec", admin, containerController.ExecInteractive)
+		dockerV1.POST("/exec", admin, containerController.Exec)
 	}
 
 	return nil
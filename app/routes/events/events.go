@@ -0,0 +1,21 @@
+package events
+
+import (
+	controller "adminDocker/app/controllers/event"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	eventService := services.NewServiceEvent(backend, logs)
+	eventController := controller.New(eventService, logs)
+
+	v1.GET("/events", middlewares.Require(models.RoleViewer), eventController.Get)
+
+	return nil
+}
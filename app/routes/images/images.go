@@ -0,0 +1,34 @@
+package images
+
+import (
+	controller "adminDocker/app/controllers/image"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	imageService := services.NewServiceImage(backend, logs)
+	imageController := controller.New(imageService, logs)
+
+	viewer := middlewares.Require(models.RoleViewer)
+	admin := middlewares.Require(models.RoleAdmin)
+
+	// Images are shared by all the containers, only unscoped admins change them.
+	// References holding a "/" are URL-encoded, "library%2Fnginx:latest".
+	imagesV1 := v1.Group("/images")
+	{
+		imagesV1.GET("", viewer, imageController.Get)
+		imagesV1.POST("", admin, middlewares.Unscoped, imageController.Pull)
+		imagesV1.GET("/:id", viewer, imageController.GetOne)
+		imagesV1.GET("/:id/history", viewer, imageController.History)
+		imagesV1.POST("/:id/tag", admin, middlewares.Unscoped, imageController.Tag)
+		imagesV1.DELETE("/:id", admin, middlewares.Unscoped, imageController.Remove)
+	}
+
+	return nil
+}
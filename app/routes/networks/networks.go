@@ -0,0 +1,33 @@
+package networks
+
+import (
+	controller "adminDocker/app/controllers/network"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	networkService := services.NewServiceNetwork(backend, logs)
+	networkController := controller.New(networkService, logs)
+
+	viewer := middlewares.Require(models.RoleViewer)
+	admin := middlewares.Require(models.RoleAdmin)
+
+	// Networks and containers out of the scope of the caller are reported as not found
+	networksV1 := v1.Group("/networks")
+	{
+		networksV1.GET("", viewer, networkController.Get)
+		networksV1.POST("", admin, networkController.Create)
+		networksV1.GET("/:id", viewer, networkController.GetOne)
+		networksV1.DELETE("/:id", admin, networkController.Remove)
+		networksV1.POST("/:id/connect", admin, networkController.Connect)
+		networksV1.POST("/:id/disconnect", admin, networkController.Disconnect)
+	}
+
+	return nil
+}
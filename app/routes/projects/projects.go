@@ -0,0 +1,35 @@
+package projects
+
+import (
+	controller "adminDocker/app/controllers/project"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	projectService := services.NewServiceProject(backend, logs)
+	projectController := controller.New(projectService, logs)
+
+	viewer := middlewares.Require(models.RoleViewer)
+	operator := middlewares.Require(models.RoleOperator)
+	admin := middlewares.Require(models.RoleAdmin)
+
+	// Projects group the containers by their docker compose labels, a scoped
+	// caller only sees and acts on the containers of its scope.
+	projectsV1 := v1.Group("/projects")
+	{
+		projectsV1.GET("", viewer, projectController.Get)
+		projectsV1.GET("/:name", viewer, projectController.GetOne)
+		projectsV1.POST("/:name/start", operator, projectController.Start)
+		projectsV1.POST("/:name/stop", operator, projectController.Stop)
+		projectsV1.POST("/:name/restart", operator, projectController.Restart)
+		projectsV1.POST("/:name/down", admin, projectController.Down)
+	}
+
+	return nil
+}
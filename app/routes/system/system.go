@@ -0,0 +1,25 @@
+package system
+
+import (
+	controller "adminDocker/app/controllers/system"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	systemService := services.NewServiceSystem(backend, logs)
+	systemController := controller.New(systemService, logs)
+
+	// A prune reaches the resources of every scope
+	systemV1 := v1.Group("/system")
+	{
+		systemV1.POST("/prune", middlewares.Require(models.RoleAdmin), middlewares.Unscoped, systemController.Prune)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package users
+
+import (
+	controller "adminDocker/app/controllers/user"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// SetupRouter registers the login on the public group and the user management on v1.
+func SetupRouter(public, v1 *gin.RouterGroup, userService *services.User, logs *zerolog.Logger) error {
+
+	userController := controller.New(userService, logs)
+
+	public.POST("/auth/login", userController.Login)
+
+	admin := middlewares.Require(models.RoleAdmin)
+
+	usersV1 := v1.Group("/users")
+	{
+		usersV1.GET("", admin, userController.Get)
+		usersV1.POST("", admin, userController.Create)
+		usersV1.PUT("/:username", admin, userController.Update)
+		usersV1.PUT("/:username/password", userController.ChangePassword)
+		usersV1.POST("/:username/password/reset", admin, userController.ResetPassword)
+	}
+
+	return nil
+}
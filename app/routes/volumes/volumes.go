@@ -0,0 +1,32 @@
+package volumes
+
+import (
+	controller "adminDocker/app/controllers/volume"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	services "adminDocker/app/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	volumeService := services.NewServiceVolume(backend, logs)
+	volumeController := controller.New(volumeService, logs)
+
+	viewer := middlewares.Require(models.RoleViewer)
+	admin := middlewares.Require(models.RoleAdmin)
+
+	// Volumes out of the scope of the caller are reported as not found.
+	// Orphans, mounted by no container, are listed with "filter=orphan:true".
+	volumesV1 := v1.Group("/volumes")
+	{
+		volumesV1.GET("", viewer, volumeController.Get)
+		volumesV1.POST("", admin, volumeController.Create)
+		volumesV1.GET("/:id", viewer, volumeController.GetOne)
+		volumesV1.DELETE("/:id", admin, volumeController.Remove)
+	}
+
+	return nil
+}
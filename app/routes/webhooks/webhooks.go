@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	controller "adminDocker/app/controllers/webhook"
+	"adminDocker/app/middlewares"
+	"adminDocker/app/models"
+	"adminDocker/app/server"
+	services "adminDocker/app/services"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func SetupRouter(v1 *gin.RouterGroup, backend services.DockerBackend, logs *zerolog.Logger) error {
+
+	eventService := services.NewServiceEvent(backend, logs)
+	webhookService, err := services.NewServiceWebhook(server.GetServer().WebhooksFile, eventService, logs)
+	if err != nil {
+		return err
+	}
+	go webhookService.Run(context.Background())
+
+	webhookController := controller.New(webhookService, logs)
+
+	webhooksV1 := v1.Group("/webhooks", middlewares.Require(models.RoleAdmin))
+	{
+		webhooksV1.GET("", webhookController.Get)
+		webhooksV1.POST("", webhookController.Create)
+		webhooksV1.DELETE("/:id", webhookController.Delete)
+		webhooksV1.GET("/:id/deliveries", webhookController.Deliveries)
+	}
+
+	return nil
+}
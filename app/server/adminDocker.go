@@ -3,6 +3,7 @@ package server
 import (
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,19 @@ type AdminDocker struct {
 	LogFormat  string
 	Mode       string
 	DockerFake bool
+	// Stats collector sampling interval and history length
+	StatsInterval  time.Duration
+	StatsRetention time.Duration
+	// Users and API keys files, lifetime of the issued tokens and password of the first admin
+	UsersFile     string
+	APIKeysFile   string
+	TokenTTL      time.Duration
+	AdminPassword string
+	// Audit JSON lines file, none when empty, and number of entries kept in memory
+	AuditFile       string
+	AuditMaxEntries int
+	// Webhooks file
+	WebhooksFile string
 }
 
 func (a *AdminDocker) ParseParameters() {
@@ -31,6 +45,36 @@ func (a *AdminDocker) ParseParameters() {
 	a.Origin = os.Getenv("ALLOW_ORIGIN")
 	a.Mode = os.Getenv("MODE")
 	a.DockerFake = os.Getenv("DOCKER_FAKE") == "TRUE"
+	a.StatsInterval = parseDuration(os.Getenv("STATS_INTERVAL"), 10*time.Second)
+	a.StatsRetention = parseDuration(os.Getenv("STATS_RETENTION"), time.Hour)
+	a.UsersFile = os.Getenv("USERS_FILE")
+	if a.UsersFile == "" {
+		a.UsersFile = "users.json"
+	}
+	a.APIKeysFile = os.Getenv("APIKEYS_FILE")
+	if a.APIKeysFile == "" {
+		a.APIKeysFile = "apikeys.json"
+	}
+	a.TokenTTL = parseDuration(os.Getenv("TOKEN_TTL"), 12*time.Hour)
+	a.AdminPassword = os.Getenv("ADMIN_PASSWORD")
+	a.AuditFile = os.Getenv("AUDIT_FILE")
+	a.AuditMaxEntries, _ = strconv.Atoi(os.Getenv("AUDIT_MAX_ENTRIES"))
+	if a.AuditMaxEntries <= 0 {
+		a.AuditMaxEntries = 10000
+	}
+	a.WebhooksFile = os.Getenv("WEBHOOKS_FILE")
+	if a.WebhooksFile == "" {
+		a.WebhooksFile = "webhooks.json"
+	}
+}
+
+// parseDuration reads a duration, falling back to def when unset or invalid.
+func parseDuration(value string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
 }
 
 // ListenAndServe listens on the TCP network address addr and then calls Serve with handler to handle requests on incoming connections.
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// apiKeyPrefix starts every key, "adk_<id>_<secret>"
+	apiKeyPrefix = "adk_"
+	// lastUsedPrecision limits the writes of the keys file on each use
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	// ErrAPIKeyInvalid is returned for unknown, malformed or revoked keys.
+	ErrAPIKeyInvalid = errors.New("invalid API key")
+	// ErrAPIKeyExpired is returned for keys past their expiry.
+	ErrAPIKeyExpired = errors.New("API key is expired")
+)
+
+// apiKeyRecord is an API key as stored in the keys file.
+type apiKeyRecord struct {
+	models.APIKey
+	SecretHash string `json:"secret_hash"`
+}
+
+// APIKey manages the API keys, stored hashed in a JSON file.
+type APIKey struct {
+	file     string
+	validate *validator.Validate
+	logs     *zerolog.Logger
+
+	mu   sync.Mutex
+	keys map[string]*apiKeyRecord
+}
+
+// NewServiceAPIKey loads the keys file.
+func NewServiceAPIKey(file string, logs *zerolog.Logger) (*APIKey, error) {
+	a := &APIKey{
+		file:     file,
+		validate: validator.New(),
+		logs:     logs,
+		keys:     make(map[string]*apiKeyRecord),
+	}
+
+	var records []*apiKeyRecord
+	if err := readJSONFile(file, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		a.keys[record.ID] = record
+	}
+	return a, nil
+}
+
+// Create issues a key for owner. The secret is returned once, only its hash is kept.
+func (a *APIKey) Create(owner string, spec models.APIKeySpec) (models.APIKeyCreated, error) {
+	if err := a.validate.Struct(spec); err != nil {
+		return models.APIKeyCreated{}, errdefs.InvalidParameter(err)
+	}
+	if spec.Role == "" {
+		spec.Role = models.RoleViewer
+	}
+	if !spec.Role.Valid() {
+		return models.APIKeyCreated{}, errInvalidRole(spec.Role)
+	}
+
+	now := time.Now().UTC()
+	var expiresAt *time.Time
+	if spec.ExpiresIn != "" {
+		d, err := time.ParseDuration(spec.ExpiresIn)
+		if err != nil || d <= 0 {
+			return models.APIKeyCreated{}, errdefs.InvalidParameter(fmt.Errorf("invalid expires_in %q, a positive duration is expected", spec.ExpiresIn))
+		}
+		expiry := now.Add(d)
+		expiresAt = &expiry
+	}
+
+	id, secret := randomHex(6), randomHex(24)
+	hash, err := functions.HashAndSalt(secret)
+	if err != nil {
+		return models.APIKeyCreated{}, err
+	}
+	key := apiKeyPrefix + id + "_" + secret
+
+	record := &apiKeyRecord{
+		APIKey: models.APIKey{
+			ID:        id,
+			Name:      spec.Name,
+			Prefix:    key[:len(apiKeyPrefix)+len(id)+5],
+			Role:      spec.Role,
+			Scope:     spec.Scope,
+			Owner:     owner,
+			CreatedAt: now,
+			ExpiresAt: expiresAt,
+		},
+		SecretHash: string(hash),
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys[id] = record
+	if err := a.save(); err != nil {
+		delete(a.keys, id)
+		return models.APIKeyCreated{}, err
+	}
+	return models.APIKeyCreated{APIKey: record.APIKey, Key: key}, nil
+}
+
+// List returns the keys, the most recent first.
+func (a *APIKey) List() []models.APIKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	keys := make([]models.APIKey, 0, len(a.keys))
+	for _, record := range a.keys {
+		keys = append(keys, record.APIKey)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys
+}
+
+// Get returns a key.
+func (a *APIKey) Get(id string) (models.APIKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record, ok := a.keys[id]
+	if !ok {
+		return models.APIKey{}, errdefs.NotFound(fmt.Errorf("API key %s not found", id))
+	}
+	return record.APIKey, nil
+}
+
+// Revoke disables a key for good. It stays listed with its revocation time.
+func (a *APIKey) Revoke(id string) (models.APIKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record, ok := a.keys[id]
+	if !ok {
+		return models.APIKey{}, errdefs.NotFound(fmt.Errorf("API key %s not found", id))
+	}
+	if record.RevokedAt != nil {
+		return models.APIKey{}, errdefs.Conflict(fmt.Errorf("API key %s is already revoked", id))
+	}
+	now := time.Now().UTC()
+	record.RevokedAt = &now
+	if err := a.save(); err != nil {
+		record.RevokedAt = nil
+		return models.APIKey{}, err
+	}
+	return record.APIKey, nil
+}
+
+// Authenticate checks a key and returns the claims of its identity, "apikey:<id>".
+func (a *APIKey) Authenticate(key string) (*models.Claims, error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	a.mu.Lock()
+	record, ok := a.keys[id]
+	var stored models.APIKey
+	var hash string
+	if ok {
+		stored, hash = record.APIKey, record.SecretHash
+	}
+	a.mu.Unlock()
+
+	// The hash is compared without the lock, bcrypt being slow on purpose
+	if !ok || stored.RevokedAt != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err := functions.CheckPassword(secret, hash); err != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+	now := time.Now().UTC()
+	if stored.ExpiresAt != nil && now.After(*stored.ExpiresAt) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	a.mu.Lock()
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= lastUsedPrecision {
+		record.LastUsedAt = &now
+		if err := a.save(); err != nil {
+			a.logs.Error().Err(err).Msg("Unable to save the last use of an API key")
+		}
+	}
+	a.mu.Unlock()
+
+	return &models.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "apikey:" + stored.ID},
+		Role:             stored.Role,
+		Scope:            stored.Scope,
+	}, nil
+}
+
+// save writes the keys file. Callers hold the lock.
+func (a *APIKey) save() error {
+	records := make([]*apiKeyRecord, 0, len(a.keys))
+	for _, record := range a.keys {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	return writeJSONFile(a.file, records)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestAPIKeys(t *testing.T) {
+	logs := zerolog.Nop()
+	file := filepath.Join(t.TempDir(), "apikeys.json")
+	keys, err := NewServiceAPIKey(file, &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := keys.Create("admin", models.APIKeySpec{Name: "ci", Role: models.RoleOperator, Scope: models.Scope{"team": "payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := keys.Authenticate(created.Key)
+	if err != nil || claims.Role != models.RoleOperator || claims.Scope["team"] != "payments" {
+		t.Fatalf("key rejected or without its role and scope: %v", err)
+	}
+	if _, err := keys.Authenticate(created.Key + "x"); !errors.Is(err, ErrAPIKeyInvalid) {
+		t.Errorf("wrong secret should be invalid, got %v", err)
+	}
+	if _, err := keys.Authenticate("garbage"); !errors.Is(err, ErrAPIKeyInvalid) {
+		t.Errorf("malformed key should be invalid, got %v", err)
+	}
+	if _, err := keys.Create("admin", models.APIKeySpec{Name: "bad", ExpiresIn: "-1h"}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("negative expiry should be refused, got %v", err)
+	}
+
+	// The last use and the hash survive a reload, never the secret
+	reloaded, err := NewServiceAPIKey(file, &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := reloaded.List()
+	if len(listed) != 1 || listed[0].LastUsedAt == nil {
+		t.Fatalf("last use not persisted: %+v", listed)
+	}
+	if _, err := reloaded.Revoke(created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.Revoke(created.ID); !errdefs.IsConflict(err) {
+		t.Errorf("second revocation should conflict, got %v", err)
+	}
+	if _, err := reloaded.Authenticate(created.Key); !errors.Is(err, ErrAPIKeyInvalid) {
+		t.Errorf("revoked key should be invalid, got %v", err)
+	}
+
+	expiring, _ := keys.Create("admin", models.APIKeySpec{Name: "short", ExpiresIn: "1ms"})
+	time.Sleep(5 * time.Millisecond)
+	if _, err := keys.Authenticate(expiring.Key); !errors.Is(err, ErrAPIKeyExpired) {
+		t.Errorf("expired key should be refused, got %v", err)
+	}
+}
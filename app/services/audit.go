@@ -0,0 +1,173 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"bufio"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// auditFilterKeys are the filter keys of the audit list endpoint.
+var auditFilterKeys = map[string]string{
+	"actor":          "",
+	"role":           "",
+	"ip":             "",
+	"method":         "",
+	"route":          "",
+	"container":      "",
+	"container_name": "",
+	"status":         "",
+	"outcome":        "",
+}
+
+// Audit keeps the audit entries in memory, the most recent max ones, and
+// appends them to a JSON lines file when one is configured.
+type Audit struct {
+	max  int
+	logs *zerolog.Logger
+
+	mu      sync.RWMutex
+	entries []models.AuditEntry
+	file    *os.File
+}
+
+// NewServiceAudit loads the last max entries of file and opens it for appending.
+// Entries are only kept in memory when file is empty.
+func NewServiceAudit(file string, max int, logs *zerolog.Logger) (*Audit, error) {
+	a := &Audit{max: max, logs: logs}
+	if file == "" {
+		return a, nil
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			logs.Warn().Err(err).Str("file", file).Msg("Skipping an unreadable audit entry")
+			continue
+		}
+		a.append(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	a.file = f
+	return a, nil
+}
+
+// Record stores an entry. Entries are never changed nor removed, but the oldest
+// ones leave the memory past max.
+func (a *Audit) Record(entry models.AuditEntry) {
+	if entry.ID == "" {
+		entry.ID = randomHex(8)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.append(entry)
+
+	if a.file == nil {
+		return
+	}
+	b, err := json.Marshal(entry)
+	if err == nil {
+		_, err = a.file.Write(append(b, '\n'))
+	}
+	if err != nil {
+		a.logs.Error().Err(err).Msg("Unable to write the audit file")
+	}
+}
+
+// List returns the entries matching the filters, filter_like and search clauses
+// of params, the most recent first unless sorted otherwise.
+func (a *Audit) List(params *models.QueryParams) ([]models.AuditEntry, error) {
+	exact, err := parseClauses(params.FilterClause, auditFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	like, err := parseClauses(params.FilterLikeClause, auditFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	entries := make([]models.AuditEntry, 0, len(a.entries))
+	for i := len(a.entries) - 1; i >= 0; i-- {
+		entry := &a.entries[i]
+		fields := func(key string) []string { return []string{auditField(entry, key)} }
+		if matchClauses(exact, false, fields, matchEqual) && matchClauses(like, true, fields, matchEqual) && matchAuditSearch(entry, params.SearchClause) {
+			entries = append(entries, *entry)
+		}
+	}
+	a.mu.RUnlock()
+
+	if err := sortItems(entries, params.SortClause); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Close closes the audit file.
+func (a *Audit) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
+
+// append adds an entry and drops the oldest ones past max. Callers hold the lock.
+func (a *Audit) append(entry models.AuditEntry) {
+	a.entries = append(a.entries, entry)
+	if a.max > 0 && len(a.entries) > a.max {
+		a.entries = append(a.entries[:0:0], a.entries[len(a.entries)-a.max:]...)
+	}
+}
+
+func auditField(e *models.AuditEntry, key string) string {
+	switch key {
+	case "actor":
+		return e.Actor
+	case "role":
+		return string(e.Role)
+	case "ip":
+		return e.SourceIP
+	case "method":
+		return e.Method
+	case "route":
+		return e.Route
+	case "container":
+		return e.Container
+	case "container_name":
+		return e.ContainerName
+	case "status":
+		return strconv.Itoa(e.Status)
+	case "outcome":
+		return e.Outcome
+	}
+	return ""
+}
+
+func matchAuditSearch(e *models.AuditEntry, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	fields := []string{e.Actor, e.SourceIP, e.Method, e.Path, e.Container, e.ContainerName, e.Outcome}
+	for key, value := range e.Parameters {
+		fields = append(fields, key+"="+value)
+	}
+	return matchSearch(keywords, fields...)
+}
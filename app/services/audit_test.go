@@ -0,0 +1,53 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestAudit(t *testing.T) {
+	logs := zerolog.Nop()
+	file := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := NewServiceAudit(file, 3, &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audit.Record(models.AuditEntry{Actor: "alice", Method: "POST", Route: "/v1/dockers/:id/stop", Container: "web", Status: 200, Outcome: "success"})
+	audit.Record(models.AuditEntry{Actor: "bob", Method: "POST", Route: "/v1/dockers/:id/kill", Container: "db", Status: 409, Outcome: "failure"})
+	audit.Record(models.AuditEntry{Actor: "alice", Method: "POST", Route: "/v1/dockers", Container: "cache", Status: 201, Outcome: "success"})
+
+	entries, err := audit.List(&models.QueryParams{FilterClause: []string{"actor:alice"}})
+	if err != nil || len(entries) != 2 || entries[0].Container != "cache" {
+		t.Errorf("alice entries expected, the most recent first, got %+v: %v", entries, err)
+	}
+	if entries, _ := audit.List(&models.QueryParams{FilterLikeClause: []string{"route:kill"}}); len(entries) != 1 || entries[0].Actor != "bob" {
+		t.Errorf("filter_like on route failed, got %+v", entries)
+	}
+	if entries, _ := audit.List(&models.QueryParams{SortClause: []string{"container"}}); len(entries) != 3 || entries[0].Container != "cache" || entries[2].Container != "web" {
+		t.Errorf("sort on container failed, got %+v", entries)
+	}
+	if _, err := audit.List(&models.QueryParams{FilterClause: []string{"colour:blue"}}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("unknown filter key should be rejected, got %v", err)
+	}
+
+	// Past max the oldest entries leave the memory, the file keeps them all
+	audit.Record(models.AuditEntry{Actor: "carol", Status: 200, Outcome: "success"})
+	if entries, _ := audit.List(&models.QueryParams{}); len(entries) != 3 || entries[2].Actor != "bob" {
+		t.Errorf("3 entries expected in memory, got %+v", entries)
+	}
+	_ = audit.Close()
+
+	reloaded, err := NewServiceAudit(file, 10, &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Close()
+	if entries, _ := reloaded.List(&models.QueryParams{}); len(entries) != 4 || entries[0].ID == "" {
+		t.Errorf("4 entries expected from the file, got %+v", entries)
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/rs/zerolog"
+)
+
+// statsWorkers is the number of containers sampled at the same time.
+const statsWorkers = 8
+
+// AllResources returns a sample of the resources used by every running container of scope.
+func (c *Container) AllResources(ctx context.Context, scope models.Scope) ([]models.Resources, error) {
+	options := container.ListOptions{Filters: filters.NewArgs()}
+	for key, value := range scope {
+		options.Filters.Add("label", key+"="+value)
+	}
+	containers, err := c.clientDocker.ContainerList(ctx, options)
+	if err != nil {
+		return nil, logError(c.logs, err)
+	}
+
+	samples := make([]models.Resources, len(containers))
+	ok := make([]bool, len(containers))
+	sem := make(chan struct{}, statsWorkers)
+	var wg sync.WaitGroup
+	for i, ct := range containers {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// A container may stop between the list and the sample, it is skipped
+			if sample, err := c.sample(ctx, id); err == nil {
+				samples[i], ok[i] = sample, true
+			}
+		}(i, ct.ID)
+	}
+	wg.Wait()
+
+	resources := make([]models.Resources, 0, len(samples))
+	for i, sample := range samples {
+		if ok[i] {
+			resources = append(resources, sample)
+		}
+	}
+	return resources, nil
+}
+
+// StatsCollector periodically samples all the running containers and keeps
+// a rolling window of their resources in memory.
+type StatsCollector struct {
+	containerService *Container
+	interval         time.Duration
+	retention        time.Duration
+	logs             *zerolog.Logger
+
+	mu      sync.RWMutex
+	history map[string][]models.Resources
+}
+
+// NewStatsCollector returns a collector sampling every interval and keeping retention of history.
+func NewStatsCollector(containerService *Container, interval, retention time.Duration, logs *zerolog.Logger) *StatsCollector {
+	return &StatsCollector{
+		containerService: containerService,
+		interval:         interval,
+		retention:        retention,
+		logs:             logs,
+		history:          make(map[string][]models.Resources),
+	}
+}
+
+// Run samples the containers until ctx is done.
+func (s *StatsCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.collect(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// History returns the samples of a container, oldest first, taken after since.
+func (s *StatsCollector) History(id string, since time.Time) []models.Resources {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := []models.Resources{}
+	for _, sample := range s.history[id] {
+		if !sample.Read.Before(since) {
+			history = append(history, sample)
+		}
+	}
+	return history
+}
+
+func (s *StatsCollector) collect(ctx context.Context) {
+	samples, err := s.containerService.AllResources(ctx, nil)
+	if err != nil {
+		s.logs.Warn().Err(err).Msg("Unable to collect containers stats")
+		return
+	}
+
+	// A retention shorter than the interval still keeps the last sample
+	size := max(int(s.retention/s.interval), 1)
+	limit := time.Now().Add(-s.retention)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sample := range samples {
+		history := append(s.history[sample.ID], sample)
+		if len(history) > size {
+			history = history[len(history)-size:]
+		}
+		s.history[sample.ID] = history
+	}
+
+	// Forget the containers which stopped before the window
+	for id, history := range s.history {
+		if len(history) == 0 || history[len(history)-1].Read.Before(limit) {
+			delete(s.history, id)
+		}
+	}
+}
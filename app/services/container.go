@@ -1,63 +1,209 @@
 package services
 
 import (
-	"adminDocker/app/server"
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog"
 )
 
+var (
+	// ErrContainerState is returned when a container is already in the requested state.
+	ErrContainerState = errors.New("container is already in the requested state")
+	// ErrAmbiguousID is returned when a short ID matches several containers.
+	ErrAmbiguousID = errors.New("multiple containers match the provided ID prefix")
+)
+
 type Container struct {
-	clientDocker *client.Client
+	clientDocker DockerBackend
 	validate     *validator.Validate
 	logs         *zerolog.Logger
 }
 
-func NewServiceContainer(logs *zerolog.Logger) (*Container, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		return nil, err
-	}
-
+func NewServiceContainer(backend DockerBackend, logs *zerolog.Logger) *Container {
 	return &Container{
-		clientDocker: cli,
+		clientDocker: backend,
 		validate:     validator.New(),
 		logs:         logs,
-	}, nil
+	}
+}
+
+// Inspect returns the low-level information of a container given its ID, name or a unique ID prefix.
+func (c *Container) Inspect(ctx context.Context, ref string) (types.ContainerJSON, error) {
+	id, err := c.resolve(ctx, ref)
+	if err != nil {
+		return types.ContainerJSON{}, logError(c.logs, err)
+	}
+	inspect, err := c.clientDocker.ContainerInspect(ctx, id)
+	if err != nil {
+		return types.ContainerJSON{}, logError(c.logs, err)
+	}
+	if inspect.ContainerJSONBase != nil && inspect.State == nil {
+		inspect.State = &types.ContainerState{}
+	}
+	return inspect, nil
 }
 
-func (c *Container) ListDocker() ([]types.Container, error) {
-	if server.GetServer().DockerFake || c.clientDocker == nil {
-		// Retourner des données factices si Docker n'est pas disponible
-		fakeContainers := []types.Container{
-			{
-				ID:     "123456789abc",
-				Names:  []string{"/fake-nginx"},
-				State:  "running",
-				Status: "Up 10 minutes",
-			},
-			{
-				ID:     "987654321xyz",
-				Names:  []string{"/fake-redis"},
-				State:  "exited",
-				Status: "Exited (0) 5 minutes ago",
-			},
+// Detail returns the low-level information of a container with the networks it is attached to, by name.
+func (c *Container) Detail(ctx context.Context, ref string) (models.ContainerDetail, error) {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return models.ContainerDetail{}, err
+	}
+
+	detail := models.ContainerDetail{ContainerJSON: inspect, Networks: []models.ContainerNetwork{}}
+	if inspect.NetworkSettings == nil {
+		return detail, nil
+	}
+	for name, endpoint := range inspect.NetworkSettings.Networks {
+		attached := models.ContainerNetwork{
+			Name:        name,
+			ID:          endpoint.NetworkID,
+			IPAddress:   endpoint.IPAddress,
+			IPPrefixLen: endpoint.IPPrefixLen,
+			Gateway:     endpoint.Gateway,
+			MacAddress:  endpoint.MacAddress,
+			Aliases:     append([]string{}, endpoint.Aliases...),
+		}
+		// A network removed meanwhile is listed as the container sees it
+		if nw, err := c.clientDocker.NetworkInspect(ctx, endpoint.NetworkID, network.InspectOptions{}); err == nil {
+			attached.Driver = nw.Driver
+			attached.Internal = nw.Internal
 		}
-		c.logs.Warn().Msg("Retour de données fake.")
-		return fakeContainers, nil
+		detail.Networks = append(detail.Networks, attached)
+	}
+	sort.Slice(detail.Networks, func(i, j int) bool { return detail.Networks[i].Name < detail.Networks[j].Name })
+	return detail, nil
+}
+
+// Authorize checks that a container carries the labels of scope, and returns its ID
+// and name. Containers out of the scope are reported as not found so that their
+// existence is not disclosed.
+func (c *Container) Authorize(ctx context.Context, ref string, scope models.Scope) (string, string, error) {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return "", "", err
 	}
+	if len(scope) > 0 && (inspect.Config == nil || !scope.Matches(inspect.Config.Labels)) {
+		return "", "", errdefs.NotFound(fmt.Errorf("no such container: %s", ref))
+	}
+	return inspect.ID, strings.TrimPrefix(inspect.Name, "/"), nil
+}
 
-	containers, err := c.clientDocker.ContainerList(context.Background(), container.ListOptions{})
+// Start starts a stopped container.
+func (c *Container) Start(ctx context.Context, ref string) error {
+	inspect, err := c.Inspect(ctx, ref)
 	if err != nil {
-		c.logs.Error().Err(err).Msg("")
-		return nil, err
+		return err
 	}
-	return containers, nil
+	if inspect.State.Running {
+		return ErrContainerState
+	}
+	return logError(c.logs, c.clientDocker.ContainerStart(ctx, inspect.ID, container.StartOptions{}))
+}
+
+// Stop stops a running container, killing it after timeout seconds.
+// A nil timeout lets the daemon use the container's default.
+func (c *Container) Stop(ctx context.Context, ref string, timeout *int, signal string) error {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if !inspect.State.Running {
+		return ErrContainerState
+	}
+	return logError(c.logs, c.clientDocker.ContainerStop(ctx, inspect.ID, container.StopOptions{Timeout: timeout, Signal: signal}))
+}
 
+// Restart stops then starts a container, whatever its current state.
+func (c *Container) Restart(ctx context.Context, ref string, timeout *int, signal string) error {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return logError(c.logs, c.clientDocker.ContainerRestart(ctx, inspect.ID, container.StopOptions{Timeout: timeout, Signal: signal}))
+}
+
+// Pause suspends all processes of a running container.
+func (c *Container) Pause(ctx context.Context, ref string) error {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if inspect.State.Paused {
+		return ErrContainerState
+	}
+	return logError(c.logs, c.clientDocker.ContainerPause(ctx, inspect.ID))
+}
+
+// Unpause resumes a paused container.
+func (c *Container) Unpause(ctx context.Context, ref string) error {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if !inspect.State.Paused {
+		return ErrContainerState
+	}
+	return logError(c.logs, c.clientDocker.ContainerUnpause(ctx, inspect.ID))
+}
+
+// Kill sends a signal (SIGKILL by default) to a running container.
+func (c *Container) Kill(ctx context.Context, ref string, signal string) error {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if !inspect.State.Running {
+		return ErrContainerState
+	}
+	return logError(c.logs, c.clientDocker.ContainerKill(ctx, inspect.ID, signal))
+}
+
+// resolve returns the full ID of a container, matching in order its ID, its name then an ID prefix.
+func (c *Container) resolve(ctx context.Context, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", errdefs.InvalidParameter(errors.New("container ID or name is required"))
+	}
+
+	containers, err := c.clientDocker.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return "", err
+	}
+
+	name := "/" + strings.TrimPrefix(ref, "/")
+	matches := []string{}
+	for _, ct := range containers {
+		if ct.ID == ref {
+			return ct.ID, nil
+		}
+		if functions.Contains(ct.Names, name) {
+			return ct.ID, nil
+		}
+		if strings.HasPrefix(ct.ID, ref) {
+			matches = append(matches, ct.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", errdefs.NotFound(fmt.Errorf("no such container: %s", ref))
+	case 1:
+		return matches[0], nil
+	default:
+		return "", ErrAmbiguousID
+	}
 }
 
 func (c *Container) Close() {
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func newTestContainer() (*Container, *FakeEngine) {
+	logs := zerolog.Nop()
+	engine := NewFakeEngine()
+	return NewServiceContainer(engine, &logs), engine
+}
+
+func TestList(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+	_, _ = c.Run(ctx, &models.ContainerSpec{Image: "nginx", Name: "payments-api", Labels: map[string]string{"team": "payments"}}, nil)
+	_, _ = c.Run(ctx, &models.ContainerSpec{Image: "redis", Name: "payments-cache", Labels: map[string]string{"team": "payments"}}, nil)
+
+	names := func(params *models.QueryParams) []string {
+		t.Helper()
+		containers, err := c.List(ctx, params, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, ct := range containers {
+			names = append(names, ct.Names[0])
+		}
+		return names
+	}
+
+	if got := names(&models.QueryParams{SortClause: []string{"name"}}); len(got) != 3 || got[0] != "/fake-nginx" {
+		t.Errorf("running containers sorted by name expected, got %v", got)
+	}
+	if got := names(&models.QueryParams{All: true, SortClause: []string{"State", "-Names"}}); len(got) != 4 || got[0] != "/fake-redis" || got[1] != "/payments-cache" {
+		t.Errorf("all containers sorted by state then name descending expected, got %v", got)
+	}
+	if got := names(&models.QueryParams{FilterClause: []string{"state:exited"}}); len(got) != 1 || got[0] != "/fake-redis" {
+		t.Errorf("exited containers expected without all, got %v", got)
+	}
+	if got := names(&models.QueryParams{FilterClause: []string{"label:team=payments", "image:redis"}}); len(got) != 1 || got[0] != "/payments-cache" {
+		t.Errorf("filters on different keys should all match, got %v", got)
+	}
+	if got := names(&models.QueryParams{FilterClause: []string{"name:fake-nginx", "name:payments-api"}}); len(got) != 2 {
+		t.Errorf("filters on the same key should match any value, got %v", got)
+	}
+	if got := names(&models.QueryParams{FilterLikeClause: []string{"name:PAY"}}); len(got) != 2 {
+		t.Errorf("filter_like should match substrings, got %v", got)
+	}
+	if got := names(&models.QueryParams{All: true, SearchClause: []string{"redis", "exited"}}); len(got) != 1 || got[0] != "/fake-redis" {
+		t.Errorf("search should match every keyword, got %v", got)
+	}
+	if _, err := c.List(ctx, &models.QueryParams{FilterClause: []string{"colour:blue"}}, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("unknown filter key should be rejected, got %v", err)
+	}
+
+	scope := models.Scope{"team": "payments"}
+	scoped, err := c.List(ctx, &models.QueryParams{All: true}, scope)
+	if err != nil || len(scoped) != 2 {
+		t.Errorf("scope should keep the payments containers, got %d: %v", len(scoped), err)
+	}
+	if id, name, err := c.Authorize(ctx, "payments-api", scope); err != nil || len(id) != 64 || name != "payments-api" {
+		t.Errorf("container in scope refused or unresolved, got %q %q: %v", id, name, err)
+	}
+	if _, _, err := c.Authorize(ctx, "fake-nginx", scope); !errdefs.IsNotFound(err) {
+		t.Errorf("container out of scope should be not found, got %v", err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+
+	if err := c.Start(ctx, "fake-nginx"); !errors.Is(err, ErrContainerState) {
+		t.Errorf("starting a running container should fail with ErrContainerState, got %v", err)
+	}
+	if err := c.Stop(ctx, "fake-nginx", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop(ctx, "fake-nginx", nil, ""); !errors.Is(err, ErrContainerState) {
+		t.Errorf("stopping a stopped container should fail with ErrContainerState, got %v", err)
+	}
+	if err := c.Start(ctx, "fake-nginx"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Pause(ctx, "fake-nginx"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Pause(ctx, "fake-nginx"); !errors.Is(err, ErrContainerState) {
+		t.Errorf("pausing a paused container should fail with ErrContainerState, got %v", err)
+	}
+	if err := c.Unpause(ctx, "fake-nginx"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Kill(ctx, "fake-nginx", "SIGKILL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Kill(ctx, "fake-nginx", ""); !errors.Is(err, ErrContainerState) {
+		t.Errorf("killing a stopped container should fail with ErrContainerState, got %v", err)
+	}
+	if err := c.Restart(ctx, "fake-redis", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(ctx, "unknown"); !errdefs.IsNotFound(err) {
+		t.Errorf("unknown container should be not found, got %v", err)
+	}
+}
+
+func TestFakeEngineEvents(t *testing.T) {
+	engine := NewFakeEngine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, _ := engine.Events(ctx, events.ListOptions{})
+	if err := engine.ContainerStop(ctx, "fake-nginx", container.StopOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var actions []events.Action
+	timeout := time.After(time.Second)
+	for len(actions) < 3 {
+		select {
+		case msg := <-messages:
+			actions = append(actions, msg.Action)
+		case <-timeout:
+			t.Fatalf("missing events, got %v", actions)
+		}
+	}
+	if actions[0] != events.ActionKill || actions[1] != events.ActionDie || actions[2] != events.ActionStop {
+		t.Errorf("unexpected events %v", actions)
+	}
+
+	if err := engine.ContainerRemove(ctx, "fake-redis", container.RemoveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.ContainerInspect(ctx, "fake-redis"); !errdefs.IsNotFound(err) {
+		t.Errorf("removed container should be not found, got %v", err)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	c, engine := newTestContainer()
+	ctx := context.Background()
+
+	byName, err := c.Inspect(ctx, "fake-nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPrefix, err := c.Inspect(ctx, byName.ID[:12])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byPrefix.ID != byName.ID {
+		t.Errorf("short ID resolved to %s instead of %s", byPrefix.ID, byName.ID)
+	}
+	if _, err := c.Inspect(ctx, "unknown"); !errdefs.IsNotFound(err) {
+		t.Errorf("unknown container should be not found, got %v", err)
+	}
+
+	// 17 containers share at least one leading hex digit
+	for i := 0; i < 15; i++ {
+		if _, err := engine.ContainerCreate(ctx, &container.Config{Image: "nginx"}, nil, nil, nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ambiguous := false
+	for _, digit := range "0123456789abcdef" {
+		if _, err := c.Inspect(ctx, string(digit)); errors.Is(err, ErrAmbiguousID) {
+			ambiguous = true
+		}
+	}
+	if !ambiguous {
+		t.Error("a one digit prefix should be ambiguous")
+	}
+}
+
+func TestLogs(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+
+	var lines []models.LogLine
+	options := container.LogsOptions{ShowStdout: true, ShowStderr: true, Timestamps: true}
+	err := c.Logs(ctx, "fake-redis", options, func(line models.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %v", lines)
+	}
+	if lines[0].Stream != "stdout" || lines[0].Message != "Starting redis-server" || lines[0].Timestamp == "" {
+		t.Errorf("unexpected first line %+v", lines[0])
+	}
+	if lines[1].Stream != "stderr" {
+		t.Errorf("unexpected second line %+v", lines[1])
+	}
+
+	options.ShowStderr = false
+	options.Tail = "1"
+	lines = nil
+	_ = c.Logs(ctx, "fake-redis", options, func(line models.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if len(lines) != 0 {
+		t.Errorf("the last line is on stderr, got %v", lines)
+	}
+
+	options.ShowStdout = false
+	if err := c.Logs(ctx, "fake-redis", options, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("selecting no stream should be invalid, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+
+	pulls := 0
+	spec := &models.ContainerSpec{Image: "alpine:3.20", Name: "worker", Ports: []string{"8080:80"}, Env: map[string]string{"MODE": "test"}}
+	created, err := c.Run(ctx, spec, func(models.PullProgress) error {
+		pulls++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Pulled || !created.Started || created.Name != "worker" || pulls == 0 {
+		t.Errorf("missing image should be pulled then started, got %+v after %d messages", created, pulls)
+	}
+
+	inspect, err := c.Inspect(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inspect.State.Running || inspect.Config.Env[0] != "MODE=test" {
+		t.Errorf("unexpected container %+v", inspect.Config)
+	}
+
+	start := false
+	created, err = c.Run(ctx, &models.ContainerSpec{Image: "alpine:3.20", Start: &start}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Pulled || created.Started {
+		t.Errorf("present image should not be pulled and container not started, got %+v", created)
+	}
+
+	if _, err := c.Run(ctx, &models.ContainerSpec{Image: "nginx", Name: "worker"}, nil); !errdefs.IsConflict(err) {
+		t.Errorf("name in use should conflict, got %v", err)
+	}
+	if _, err := c.Run(ctx, &models.ContainerSpec{Image: "nginx", RestartPolicy: "sometimes"}, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("invalid restart policy should be rejected, got %v", err)
+	}
+	if _, err := c.Run(ctx, &models.ContainerSpec{Image: "unknown", Pull: "never"}, nil); !errdefs.IsNotFound(err) {
+		t.Errorf("missing image should not be pulled, got %v", err)
+	}
+}
+
+func TestAuthorizeBinds(t *testing.T) {
+	c, engine := newTestContainer()
+	ctx := context.Background()
+	scope := models.Scope{"team": "payments"}
+
+	_, _ = engine.VolumeCreate(ctx, volume.CreateOptions{Name: "payments-data", Labels: map[string]string{"team": "payments"}})
+	_, _ = engine.VolumeCreate(ctx, volume.CreateOptions{Name: "billing-data", Labels: map[string]string{"team": "billing"}})
+
+	if err := c.AuthorizeBinds(ctx, []string{"payments-data:/data", "payments-data:/backup:ro"}, scope); err != nil {
+		t.Errorf("volumes of the scope should be mounted: %v", err)
+	}
+	for _, bind := range []string{"/var/run/docker.sock:/var/run/docker.sock", "/:/host", "./data:/data", "billing-data:/data", "missing:/data"} {
+		if err := c.AuthorizeBinds(ctx, []string{bind}, scope); !errdefs.IsForbidden(err) {
+			t.Errorf("bind %s should be refused, got %v", bind, err)
+		}
+	}
+	if err := c.AuthorizeBinds(ctx, []string{"/:/host"}, nil); err != nil {
+		t.Errorf("unscoped callers may mount host paths: %v", err)
+	}
+}
+
+func TestGroupContainers(t *testing.T) {
+	containers := []types.Container{
+		{ID: "a", State: "running", Labels: map[string]string{ComposeProjectLabel: "shop", "team": "payments"}},
+		{ID: "b", State: "exited", Labels: map[string]string{ComposeProjectLabel: "shop"}},
+		{ID: "c", State: "running", Labels: map[string]string{"team": "payments"}},
+	}
+
+	groups, err := GroupContainers(containers, "State", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].Value != "running" || groups[0].Count != 2 || len(groups[0].IDs) != 2 {
+		t.Errorf("unexpected state groups %+v", groups)
+	}
+
+	groups, _ = GroupContainers(containers, "project", false)
+	if len(groups) != 2 || groups[0].Value != "shop" || groups[0].IDs != nil {
+		t.Errorf("unexpected project groups %+v", groups)
+	}
+
+	for _, col := range []string{"team", "Labels.team"} {
+		groups, _ = GroupContainers(containers, col, false)
+		if groups[0].Value != "payments" || groups[0].Count != 2 {
+			t.Errorf("unexpected label groups for %s: %+v", col, groups)
+		}
+	}
+}
+
+func TestExec(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+
+	if _, err := c.Exec(ctx, "fake-redis", &models.ExecSpec{Cmd: []string{"sh"}}); !errdefs.IsConflict(err) {
+		t.Errorf("exec in a stopped container should fail on its state, got %v", err)
+	}
+
+	session, err := c.Exec(ctx, "fake-nginx", &models.ExecSpec{Cmd: []string{"/bin/sh"}, User: "www-data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	go func() {
+		_, _ = session.Write([]byte("whoami\nunknown\nexit 3\n"))
+	}()
+
+	var stdout, stderr strings.Builder
+	if err := session.Output(&stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "www-data\n" || stderr.String() != "sh: unknown: not found\n" {
+		t.Errorf("unexpected outputs %q and %q", stdout.String(), stderr.String())
+	}
+	if code, err := session.ExitCode(ctx); err != nil || code != 3 {
+		t.Errorf("exit code 3 expected, got %d: %v", code, err)
+	}
+}
+
+func TestExecKill(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+
+	session, err := c.Exec(ctx, "fake-nginx", &models.ExecSpec{Cmd: []string{"sh"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	stdout, writer := io.Pipe()
+	go func() {
+		_ = session.Output(writer, writer)
+		writer.Close()
+	}()
+	if _, err := session.Write([]byte("echo started; sleep 100\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The pid is not part of the output
+	lines := bufio.NewReader(stdout)
+	if line, err := lines.ReadString('\n'); err != nil || line != "started\n" {
+		t.Fatalf("output should start with the command, got %q: %v", line, err)
+	}
+
+	// The daemon reports the pid of the host, the kill needs the one of the container
+	inspect, err := c.clientDocker.ContainerExecInspect(ctx, session.ID)
+	if err != nil || inspect.Pid == session.pid {
+		t.Fatalf("exec should have a host pid apart from %d, got %+v: %v", session.pid, inspect, err)
+	}
+	if err := session.Kill(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if code, err := session.ExitCode(ctx); err != nil || code != 137 {
+		t.Errorf("exit code 137 expected, got %d: %v", code, err)
+	}
+	if rest, _ := io.ReadAll(lines); len(rest) > 0 {
+		t.Errorf("nothing else expected, got %q", rest)
+	}
+
+	running, err := c.Exec(ctx, "fake-nginx", &models.ExecSpec{Cmd: []string{"sleep", "100"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer running.Close()
+	running.pid = 0
+	if err := running.Kill(ctx); err == nil {
+		t.Error("an exec without its pid cannot be killed")
+	}
+}
+
+func TestRunExec(t *testing.T) {
+	c, _ := newTestContainer()
+	ctx := context.Background()
+
+	result, err := c.RunExec(ctx, "fake-nginx", &models.ExecSpec{Cmd: []string{"sh", "-c", "env; pwd; nope"}, Env: map[string]string{"A": "1"}, WorkDir: "/srv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode == nil || *result.ExitCode != 127 || result.TimedOut {
+		t.Errorf("exit code 127 expected, got %+v", result)
+	}
+	if !strings.Contains(result.Stdout, "A=1\n") || !strings.HasSuffix(result.Stdout, "/srv\n") || result.Stderr != "sh: nope: not found\n" {
+		t.Errorf("unexpected outputs %q and %q", result.Stdout, result.Stderr)
+	}
+
+	result, err = c.RunExec(ctx, "fake-nginx", &models.ExecSpec{Cmd: []string{"sleep", "10"}, Timeout: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.TimedOut || result.DurationMs > 5000 {
+		t.Errorf("command should time out after 1s, got %+v", result)
+	}
+	// sleep ignores the end of its streams, only the kill ends it
+	if result.ExitCode == nil || *result.ExitCode != 137 {
+		t.Errorf("command should be killed at the timeout, got %+v", result)
+	}
+	if inspect, err := c.clientDocker.ContainerExecInspect(ctx, result.ExecID); err != nil || inspect.Running {
+		t.Errorf("exec should not run anymore, got %+v: %v", inspect, err)
+	}
+	if _, err := c.RunExec(ctx, "fake-nginx", &models.ExecSpec{}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("missing command should be refused, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/rs/zerolog"
+)
+
+// eventFilterKeys maps the filter keys of the events endpoint to the daemon filter
+// they are pushed down to.
+var eventFilterKeys = map[string]string{
+	"type":      "type",
+	"action":    "event",
+	"label":     "label",
+	"container": "container",
+	"image":     "image",
+	"network":   "network",
+	"volume":    "volume",
+}
+
+type Event struct {
+	clientDocker DockerBackend
+	logs         *zerolog.Logger
+}
+
+func NewServiceEvent(backend DockerBackend, logs *zerolog.Logger) *Event {
+	return &Event{
+		clientDocker: backend,
+		logs:         logs,
+	}
+}
+
+// CheckEventFilters validates the filter clauses of Subscribe.
+func CheckEventFilters(filterClauses []string) error {
+	_, err := parseClauses(filterClauses, eventFilterKeys)
+	return err
+}
+
+// Subscribe relays the daemon events of scope matching the filter clauses, until ctx is done.
+// Several values of a key match any of them, while all the labels are required.
+func (e *Event) Subscribe(ctx context.Context, filterClauses []string, scope models.Scope, send func(models.Event) error) error {
+	clauses, err := parseClauses(filterClauses, eventFilterKeys)
+	if err != nil {
+		return err
+	}
+
+	args := filters.NewArgs()
+	for key, values := range clauses {
+		for _, value := range values {
+			args.Add(eventFilterKeys[key], value)
+		}
+	}
+	for key, value := range scope {
+		args.Add("label", key+"="+value)
+	}
+
+	messages, errs := e.clientDocker.Events(ctx, events.ListOptions{Filters: args})
+	for {
+		select {
+		case msg := <-messages:
+			if err := send(toEvent(msg)); err != nil {
+				return err
+			}
+		case err := <-errs:
+			if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+				return nil
+			}
+			e.logs.Error().Err(err).Msg("")
+			return err
+		}
+	}
+}
+
+// toEvent converts a daemon event message.
+func toEvent(msg events.Message) models.Event {
+	event := models.Event{
+		Type:       string(msg.Type),
+		Action:     string(msg.Action),
+		ActorID:    msg.Actor.ID,
+		Name:       msg.Actor.Attributes["name"],
+		Attributes: msg.Actor.Attributes,
+		Time:       time.Unix(0, msg.TimeNano).UTC(),
+	}
+	if msg.TimeNano == 0 {
+		event.Time = time.Unix(msg.Time, 0).UTC()
+	}
+	return event
+}
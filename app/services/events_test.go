@@ -0,0 +1,59 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestEventSubscribe(t *testing.T) {
+	logs := zerolog.Nop()
+	c, engine := newTestContainer()
+	events := NewServiceEvent(engine, &logs)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := events.Subscribe(ctx, []string{"colour:blue"}, nil, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("unknown filter key should be rejected, got %v", err)
+	}
+
+	received := make(chan models.Event, 8)
+	done := make(chan error, 1)
+	go func() {
+		done <- events.Subscribe(ctx, []string{"type:container", "action:die"}, models.Scope{"team": "payments"}, func(event models.Event) error {
+			received <- event
+			return nil
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Out of scope, then in scope
+	_ = c.Stop(ctx, "fake-nginx", nil, "")
+	if _, err := c.Run(ctx, &models.ContainerSpec{Image: "nginx", Name: "payments-api", Labels: map[string]string{"team": "payments"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Stop(ctx, "payments-api", nil, "")
+
+	select {
+	case event := <-received:
+		if event.Action != "die" || event.Name != "payments-api" || event.Time.IsZero() {
+			t.Errorf("die event of payments-api expected, got %+v", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("missing event")
+	}
+	select {
+	case event := <-received:
+		t.Errorf("unexpected event %+v", event)
+	default:
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("subscription should end without error, got %v", err)
+	}
+}
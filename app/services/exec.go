@@ -0,0 +1,320 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// execPidScript prints the pid of a command in the container before replacing itself
+// with the command. The daemon reports the pid of the host, unknown in the container.
+const execPidScript = `echo $$; exec "$@"`
+
+// ExecSession is an exec instance attached to its input and outputs.
+type ExecSession struct {
+	ID       string
+	Tty      bool
+	backend  DockerBackend
+	hijacked types.HijackedResponse
+	// output are the outputs of the command, once its pid is read
+	output io.Reader
+	// pid of the command in the container, 0 when unknown
+	pid int
+}
+
+// Exec starts a command in a running container and attaches to it.
+func (c *Container) Exec(ctx context.Context, ref string, spec *models.ExecSpec) (*ExecSession, error) {
+	if err := c.validate.Struct(spec); err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !inspect.State.Running || inspect.State.Paused {
+		return nil, errdefs.Conflict(fmt.Errorf("container %s is not running", ref))
+	}
+
+	options := container.ExecOptions{
+		User:         spec.User,
+		Tty:          spec.Tty,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          envList(spec.Env),
+		WorkingDir:   spec.WorkDir,
+		Cmd:          append([]string{"sh", "-c", execPidScript, "sh"}, spec.Cmd...),
+	}
+	var consoleSize *[2]uint
+	if spec.Tty && spec.Height > 0 && spec.Width > 0 {
+		consoleSize = &[2]uint{spec.Height, spec.Width}
+		options.ConsoleSize = consoleSize
+	}
+
+	session, err := c.startExec(ctx, inspect.ID, options, consoleSize)
+	if err != nil {
+		return nil, err
+	}
+	session.pid, session.output = readExecPid(session.hijacked.Reader, spec.Tty)
+	if session.pid != 0 {
+		return session, nil
+	}
+	// Images without a shell cannot run the script, the command then runs as is
+	// and cannot be killed
+	if code, err := session.ExitCode(ctx); err == nil && (code == 126 || code == 127) {
+		session.Close()
+		options.Cmd = spec.Cmd
+		return c.startExec(ctx, inspect.ID, options, consoleSize)
+	}
+	return session, nil
+}
+
+// startExec creates an exec instance and attaches to it.
+func (c *Container) startExec(ctx context.Context, containerID string, options container.ExecOptions, consoleSize *[2]uint) (*ExecSession, error) {
+	created, err := c.clientDocker.ContainerExecCreate(ctx, containerID, options)
+	if err != nil {
+		return nil, logError(c.logs, err)
+	}
+	hijacked, err := c.clientDocker.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{Tty: options.Tty, ConsoleSize: consoleSize})
+	if err != nil {
+		return nil, logError(c.logs, err)
+	}
+	return &ExecSession{ID: created.ID, Tty: options.Tty, backend: c.clientDocker, hijacked: hijacked, output: hijacked.Reader}, nil
+}
+
+// readExecPid reads the pid execPidScript prints on the first line of the output, and
+// returns the rest of the output. Without a pid, what was read is given back.
+func readExecPid(r *bufio.Reader, tty bool) (int, io.Reader) {
+	if tty {
+		line, _ := r.ReadString('\n')
+		if pid, err := strconv.Atoi(strings.TrimSpace(line)); err == nil && pid > 0 {
+			return pid, r
+		}
+		return 0, io.MultiReader(strings.NewReader(line), r)
+	}
+
+	// The outputs are multiplexed in frames: the stream, 3 zero bytes, the size
+	// and the data. Frames of stderr before the line are kept as they are.
+	var read, kept bytes.Buffer
+	var stdout []byte
+	header := make([]byte, 8)
+	for len(stdout) < 32 && bytes.IndexByte(stdout, '\n') < 0 {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0, io.MultiReader(&read, r)
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			read.Write(header)
+			read.Write(data)
+			return 0, io.MultiReader(&read, r)
+		}
+		read.Write(header)
+		read.Write(data)
+		if stdcopy.StdType(header[0]) == stdcopy.Stdout {
+			stdout = append(stdout, data...)
+		} else {
+			kept.Write(header)
+			kept.Write(data)
+		}
+	}
+
+	line, rest, _ := bytes.Cut(stdout, []byte("\n"))
+	pid, err := strconv.Atoi(strings.TrimSpace(string(line)))
+	if err != nil || pid <= 0 {
+		return 0, io.MultiReader(&read, r)
+	}
+	if len(rest) > 0 {
+		binary.BigEndian.PutUint32(header[4:], uint32(len(rest)))
+		header[0] = byte(stdcopy.Stdout)
+		kept.Write(header)
+		kept.Write(rest)
+	}
+	return pid, io.MultiReader(&kept, r)
+}
+
+// Write sends p to the input of the command.
+func (s *ExecSession) Write(p []byte) (int, error) {
+	return s.hijacked.Conn.Write(p)
+}
+
+// CloseInput closes the input of the command, when the connection allows it.
+func (s *ExecSession) CloseInput() error {
+	if cw, ok := s.hijacked.Conn.(types.CloseWriter); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
+
+// Resize changes the size of the terminal of the command.
+func (s *ExecSession) Resize(ctx context.Context, height, width uint) error {
+	return s.backend.ContainerExecResize(ctx, s.ID, container.ResizeOptions{Height: height, Width: width})
+}
+
+// Output copies the outputs of the command until it ends, demultiplexed unless it has a TTY.
+func (s *ExecSession) Output(stdout, stderr io.Writer) error {
+	var err error
+	if s.Tty {
+		_, err = io.Copy(stdout, s.output)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, s.output)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+		return nil
+	}
+	return err
+}
+
+// ExitCode waits for the end of the command, for a few seconds at most, and returns its exit code.
+func (s *ExecSession) ExitCode(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		inspect, err := s.backend.ContainerExecInspect(ctx, s.ID)
+		if err != nil {
+			return 0, err
+		}
+		if !inspect.Running {
+			return inspect.ExitCode, nil
+		}
+		if time.Now().After(deadline) {
+			return 0, errdefs.Conflict(errors.New("exec is still running"))
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// Kill ends the command with SIGKILL, and its process group when it leads one. The
+// daemon cannot signal an exec instance, a kill of its pid is run in the container.
+func (s *ExecSession) Kill(ctx context.Context) error {
+	inspect, err := s.backend.ContainerExecInspect(ctx, s.ID)
+	if err != nil {
+		return err
+	}
+	if !inspect.Running {
+		return nil
+	}
+	if s.pid == 0 {
+		return fmt.Errorf("exec %s cannot be killed, its pid in the container is unknown", s.ID)
+	}
+
+	pid := strconv.Itoa(s.pid)
+	created, err := s.backend.ContainerExecCreate(ctx, inspect.ContainerID, container.ExecOptions{
+		User: "root",
+		Cmd:  []string{"sh", "-c", "kill -9 -" + pid + " 2>/dev/null || kill -9 " + pid},
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.backend.ContainerExecStart(ctx, created.ID, container.ExecStartOptions{Detach: true}); err != nil {
+		return err
+	}
+	if _, err := s.ExitCode(ctx); err != nil {
+		return fmt.Errorf("exec %s could not be killed: %w", s.ID, err)
+	}
+	return nil
+}
+
+// Close detaches from the command.
+func (s *ExecSession) Close() {
+	s.hijacked.Close()
+}
+
+const (
+	// execTimeout is the default timeout of the one-shot commands
+	execTimeout = 30 * time.Second
+	// execOutputLimit is the size kept of each output of the one-shot commands
+	execOutputLimit = 1024 * 1024
+)
+
+// RunExec runs a command to completion and returns its outputs and exit code.
+// At the timeout the command is killed and its streams are closed.
+func (c *Container) RunExec(ctx context.Context, ref string, spec *models.ExecSpec) (models.ExecResult, error) {
+	timeout := execTimeout
+	if spec.Timeout > 0 {
+		timeout = time.Duration(spec.Timeout) * time.Second
+	}
+	spec.Tty = false
+
+	start := time.Now()
+	session, err := c.Exec(ctx, ref, spec)
+	if err != nil {
+		return models.ExecResult{}, err
+	}
+	defer session.Close()
+	_ = session.CloseInput()
+
+	stdout, stderr := &limitedBuffer{limit: execOutputLimit}, &limitedBuffer{limit: execOutputLimit}
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Output(stdout, stderr)
+	}()
+
+	result := models.ExecResult{ExecID: session.ID}
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		result.TimedOut = true
+		killErr := session.Kill(ctx)
+		session.Close()
+		err = <-done
+		if killErr != nil {
+			return models.ExecResult{}, logError(c.logs, fmt.Errorf("command timed out after %s: %w", timeout, killErr))
+		}
+	case <-ctx.Done():
+		// The request is gone, the kill outlives it
+		if err := session.Kill(context.WithoutCancel(ctx)); err != nil {
+			logError(c.logs, err)
+		}
+		session.Close()
+		<-done
+		return models.ExecResult{}, ctx.Err()
+	}
+	if err != nil && !result.TimedOut {
+		return models.ExecResult{}, logError(c.logs, err)
+	}
+
+	if exitCode, err := session.ExitCode(ctx); err == nil {
+		result.ExitCode = &exitCode
+	} else if !result.TimedOut {
+		return models.ExecResult{}, logError(c.logs, err)
+	}
+	result.Stdout, result.Stderr = stdout.String(), stderr.String()
+	result.Truncated = stdout.truncated || stderr.truncated
+	result.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	return result, nil
+}
+
+// limitedBuffer keeps the first limit bytes written, and drops the next ones.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
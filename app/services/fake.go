@@ -0,0 +1,941 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// FakeEngine is an in-memory Docker engine.
+// Containers can be created, started, stopped and removed, and every state
+// change is published on the event stream like the daemon does.
+type FakeEngine struct {
+	mu          sync.RWMutex
+	containers  map[string]*types.ContainerJSON
+	images      map[string]*image.Summary
+	subscribers map[chan events.Message]struct{}
+	logs        map[string][]fakeLog
+	stats       map[string]*fakeStats
+	execs       map[string]*fakeExec
+	networks    map[string]*network.Inspect
+	volumes     map[string]*volume.Volume
+	buildCache  map[string]*types.BuildCache
+	sizes       map[string]int64
+	nextIP      int
+	nextPid     int
+}
+
+type fakeLog struct {
+	time   time.Time
+	stream stdcopy.StdType
+	text   string
+}
+
+// fakeStats holds the cumulated counters of a container.
+type fakeStats struct {
+	read       time.Time
+	cpu        uint64
+	system     uint64
+	rx, tx     uint64
+	blockRead  uint64
+	blockWrite uint64
+}
+
+// NewFakeEngine returns a fake engine holding a running nginx and an exited redis,
+// the predefined networks and some build cache.
+func NewFakeEngine() *FakeEngine {
+	f := &FakeEngine{
+		containers:  make(map[string]*types.ContainerJSON),
+		images:      make(map[string]*image.Summary),
+		subscribers: make(map[chan events.Message]struct{}),
+		logs:        make(map[string][]fakeLog),
+		stats:       make(map[string]*fakeStats),
+		execs:       make(map[string]*fakeExec),
+		networks:    make(map[string]*network.Inspect),
+		volumes:     make(map[string]*volume.Volume),
+		buildCache:  make(map[string]*types.BuildCache),
+		sizes:       make(map[string]int64),
+		nextIP:      2,
+		nextPid:     1000,
+	}
+
+	f.addImage("nginx:latest", 192*1024*1024)
+	f.addImage("redis:latest", 117*1024*1024)
+	f.addNetwork("bridge", "bridge", nil)
+	f.addNetwork("host", "host", nil)
+	f.addNetwork("none", "null", nil)
+	f.addBuildCache()
+
+	ctx := context.Background()
+	nginx, _ := f.ContainerCreate(ctx, &container.Config{
+		Image:        "nginx:latest",
+		Cmd:          []string{"nginx", "-g", "daemon off;"},
+		ExposedPorts: nat.PortSet{"80/tcp": {}},
+	}, &container.HostConfig{
+		PortBindings: nat.PortMap{"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}}},
+	}, nil, nil, "fake-nginx")
+	_ = f.ContainerStart(ctx, nginx.ID, container.StartOptions{})
+	f.mu.Lock()
+	f.log(nginx.ID, stdcopy.Stdout, `172.17.0.1 - - [`+time.Now().Format("02/Jan/2006:15:04:05 -0700")+`] "GET / HTTP/1.1" 200 615 "-" "curl/8.5.0"`)
+	f.mu.Unlock()
+
+	redis, _ := f.ContainerCreate(ctx, &container.Config{
+		Image: "redis:latest",
+		Cmd:   []string{"redis-server"},
+	}, nil, nil, nil, "fake-redis")
+	_ = f.ContainerStart(ctx, redis.ID, container.StartOptions{})
+	_ = f.ContainerStop(ctx, redis.ID, container.StopOptions{})
+
+	return f
+}
+
+// ContainerList lists running containers, or all of them with options.All.
+func (f *FakeEngine) ContainerList(_ context.Context, options container.ListOptions) ([]types.Container, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	list := make([]types.Container, 0, len(f.containers))
+	for _, c := range f.containers {
+		if !options.All && !c.State.Running && !options.Filters.Contains("status") {
+			continue
+		}
+		summary := f.summary(c)
+		if !matchContainer(options.Filters, &summary) {
+			continue
+		}
+		list = append(list, summary)
+	}
+
+	// Newest first, like the daemon
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created == list[j].Created {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Created > list[j].Created
+	})
+	if options.Limit > 0 && len(list) > options.Limit {
+		list = list[:options.Limit]
+	}
+	return list, nil
+}
+
+// ContainerInspect returns a copy of the container matching an ID, a name or a unique ID prefix.
+func (f *FakeEngine) ContainerInspect(_ context.Context, ref string) (types.ContainerJSON, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return types.ContainerJSON{}, err
+	}
+	return cloneContainer(c), nil
+}
+
+// ContainerCreate registers a new container in the created state.
+func (f *FakeEngine) ContainerCreate(_ context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, _ *ocispec.Platform, name string) (container.CreateResponse, error) {
+	if config == nil || config.Image == "" {
+		return container.CreateResponse{}, errdefs.InvalidParameter(fmt.Errorf("config.Image is required"))
+	}
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	img, err := f.lookupImage(config.Image)
+	if err != nil {
+		return container.CreateResponse{}, err
+	}
+
+	id := randomID()
+	if name == "" {
+		name = "fake_" + id[:8]
+	}
+	name = strings.TrimPrefix(name, "/")
+	for _, c := range f.containers {
+		if c.Name == "/"+name {
+			return container.CreateResponse{}, errdefs.Conflict(fmt.Errorf("Conflict. The container name \"/%s\" is already in use by container \"%s\"", name, c.ID))
+		}
+	}
+
+	networks, err := f.endpoints(hostConfig, networkingConfig)
+	if err != nil {
+		return container.CreateResponse{}, err
+	}
+
+	cfg := *config
+	cfg.Hostname = id[:12]
+	cmd := append(cfg.Entrypoint[:len(cfg.Entrypoint):len(cfg.Entrypoint)], cfg.Cmd...)
+	path, args := "", []string{}
+	if len(cmd) > 0 {
+		path, args = cmd[0], cmd[1:]
+	}
+
+	c := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:         id,
+			Created:    time.Now().UTC().Format(time.RFC3339Nano),
+			Path:       path,
+			Args:       args,
+			State:      &types.ContainerState{Status: "created"},
+			Image:      img.ID,
+			Name:       "/" + name,
+			Driver:     "overlay2",
+			Platform:   "linux",
+			HostConfig: hostConfig,
+		},
+		Config:          &cfg,
+		Mounts:          fakeMounts(hostConfig),
+		NetworkSettings: &types.NetworkSettings{Networks: networks},
+	}
+	f.addVolumes(c)
+	f.containers[id] = c
+	f.sizes[id] = mathrand.Int63n(8 * 1024 * 1024)
+	f.publish(c, events.ActionCreate, nil)
+
+	return container.CreateResponse{ID: id}, nil
+}
+
+// ContainerStart starts a container; starting a running container is a no-op.
+func (f *FakeEngine) ContainerStart(_ context.Context, ref string, _ container.StartOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		return nil
+	}
+	f.start(c)
+	return nil
+}
+
+// ContainerStop stops a container; stopping a stopped container is a no-op.
+func (f *FakeEngine) ContainerStop(_ context.Context, ref string, options container.StopOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if !c.State.Running {
+		return nil
+	}
+	f.stop(c, options.Signal)
+	f.publish(c, events.ActionStop, nil)
+	return nil
+}
+
+// ContainerRestart stops the container if needed then starts it.
+func (f *FakeEngine) ContainerRestart(_ context.Context, ref string, options container.StopOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		f.stop(c, options.Signal)
+		f.publish(c, events.ActionStop, nil)
+	}
+	f.start(c)
+	f.publish(c, events.ActionRestart, nil)
+	return nil
+}
+
+// ContainerPause pauses a running container.
+func (f *FakeEngine) ContainerPause(_ context.Context, ref string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if !c.State.Running {
+		return errdefs.Conflict(fmt.Errorf("container %s is not running", c.ID))
+	}
+	if c.State.Paused {
+		return errdefs.Conflict(fmt.Errorf("container %s is already paused", c.ID))
+	}
+	c.State.Paused = true
+	c.State.Status = "paused"
+	f.publish(c, events.ActionPause, nil)
+	return nil
+}
+
+// ContainerUnpause resumes a paused container.
+func (f *FakeEngine) ContainerUnpause(_ context.Context, ref string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if !c.State.Paused {
+		return errdefs.Conflict(fmt.Errorf("container %s is not paused", c.ID))
+	}
+	c.State.Paused = false
+	c.State.Status = "running"
+	f.publish(c, events.ActionUnPause, nil)
+	return nil
+}
+
+// ContainerKill sends a signal to a running container, which exits.
+func (f *FakeEngine) ContainerKill(_ context.Context, ref string, signal string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if !c.State.Running {
+		return errdefs.Conflict(fmt.Errorf("cannot kill container: %s: container %s is not running", ref, c.ID))
+	}
+	if signal == "" {
+		signal = "SIGKILL"
+	}
+	f.stop(c, signal)
+	return nil
+}
+
+// ContainerRemove deletes a container; running containers need options.Force.
+func (f *FakeEngine) ContainerRemove(_ context.Context, ref string, options container.RemoveOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		if !options.Force {
+			return errdefs.Conflict(fmt.Errorf("you cannot remove a running container %s. Stop the container before attempting removal or force remove", c.ID))
+		}
+		f.stop(c, "SIGKILL")
+	}
+	f.remove(c, options.RemoveVolumes)
+	return nil
+}
+
+// Events streams engine events matching options.Filters until ctx is done.
+func (f *FakeEngine) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	sub := make(chan events.Message, 64)
+	f.mu.Lock()
+	f.subscribers[sub] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		defer func() {
+			f.mu.Lock()
+			delete(f.subscribers, sub)
+			f.mu.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case msg := <-sub:
+				if !matchEvent(options.Filters, msg) {
+					continue
+				}
+				select {
+				case messages <- msg:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+// ContainerLogs returns the recorded output of a container, multiplexed unless it has a TTY.
+// With options.Follow new lines are streamed until the container stops or ctx is done.
+func (f *FakeEngine) ContainerLogs(ctx context.Context, ref string, options container.LogsOptions) (io.ReadCloser, error) {
+	f.mu.RLock()
+	c, err := f.lookup(ref)
+	if err != nil {
+		f.mu.RUnlock()
+		return nil, err
+	}
+	id, tty := c.ID, c.Config.Tty
+	f.mu.RUnlock()
+
+	since, err := logTime(options.Since)
+	if err != nil {
+		return nil, err
+	}
+	until, err := logTime(options.Until)
+	if err != nil {
+		return nil, err
+	}
+	tail := -1
+	if options.Tail != "" && options.Tail != "all" {
+		if tail, err = strconv.Atoi(options.Tail); err != nil {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("invalid tail value %q", options.Tail))
+		}
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		stdout, stderr := io.Writer(writer), io.Writer(writer)
+		if !tty {
+			stdout = stdcopy.NewStdWriter(writer, stdcopy.Stdout)
+			stderr = stdcopy.NewStdWriter(writer, stdcopy.Stderr)
+		}
+		write := func(l fakeLog) error {
+			if (l.stream == stdcopy.Stdout && !options.ShowStdout) || (l.stream == stdcopy.Stderr && !options.ShowStderr) {
+				return nil
+			}
+			if (!since.IsZero() && l.time.Before(since)) || (!until.IsZero() && l.time.After(until)) {
+				return nil
+			}
+			line := l.text + "\n"
+			if options.Timestamps {
+				line = l.time.UTC().Format(time.RFC3339Nano) + " " + line
+			}
+			out := stdout
+			if l.stream == stdcopy.Stderr {
+				out = stderr
+			}
+			_, err := out.Write([]byte(line))
+			return err
+		}
+
+		f.mu.RLock()
+		lines := append([]fakeLog(nil), f.logs[id]...)
+		f.mu.RUnlock()
+		sent := len(lines)
+		if tail >= 0 && tail < len(lines) {
+			lines = lines[len(lines)-tail:]
+		}
+		for _, l := range lines {
+			if err := write(l); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+
+		if !options.Follow {
+			writer.Close()
+			return
+		}
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+				return
+			case <-ticker.C:
+			}
+			f.mu.RLock()
+			c, ok := f.containers[id]
+			running := ok && c.State.Running
+			lines := append([]fakeLog(nil), f.logs[id][min(sent, len(f.logs[id])):]...)
+			f.mu.RUnlock()
+			sent += len(lines)
+			for _, l := range lines {
+				if err := write(l); err != nil {
+					writer.CloseWithError(err)
+					return
+				}
+			}
+			if !running || (!until.IsZero() && time.Now().After(until)) {
+				writer.Close()
+				return
+			}
+		}
+	}()
+
+	return reader, nil
+}
+
+// ContainerStats returns a sample of the container resources, or a sample per second with stream.
+func (f *FakeEngine) ContainerStats(ctx context.Context, ref string, stream bool) (container.StatsResponseReader, error) {
+	f.mu.RLock()
+	c, err := f.lookup(ref)
+	f.mu.RUnlock()
+	if err != nil {
+		return container.StatsResponseReader{}, err
+	}
+	id := c.ID
+
+	reader, writer := io.Pipe()
+	go func() {
+		encoder := json.NewEncoder(writer)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			f.mu.Lock()
+			c, ok := f.containers[id]
+			var sample container.StatsResponse
+			running := false
+			if ok {
+				sample = f.sample(c)
+				running = c.State.Running
+			}
+			f.mu.Unlock()
+			if !ok {
+				writer.Close()
+				return
+			}
+			if err := encoder.Encode(sample); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			if !stream || !running {
+				writer.Close()
+				return
+			}
+			select {
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return container.StatsResponseReader{Body: reader, OSType: "linux"}, nil
+}
+
+// Close releases nothing, the fake engine holds no connection.
+func (f *FakeEngine) Close() error {
+	return nil
+}
+
+// lookup resolves a full ID, a name or a unique ID prefix. Callers hold the lock.
+func (f *FakeEngine) lookup(ref string) (*types.ContainerJSON, error) {
+	if c, ok := f.containers[ref]; ok {
+		return c, nil
+	}
+	name := "/" + strings.TrimPrefix(ref, "/")
+	for _, c := range f.containers {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	var found *types.ContainerJSON
+	if ref != "" {
+		for id, c := range f.containers {
+			if strings.HasPrefix(id, ref) {
+				if found != nil {
+					return nil, errdefs.InvalidParameter(fmt.Errorf("multiple IDs found with provided prefix: %s", ref))
+				}
+				found = c
+			}
+		}
+	}
+	if found == nil {
+		return nil, errdefs.NotFound(fmt.Errorf("No such container: %s", ref))
+	}
+	return found, nil
+}
+
+// start moves a container to the running state. Callers hold the lock.
+func (f *FakeEngine) start(c *types.ContainerJSON) {
+	c.State.Running = true
+	c.State.Paused = false
+	c.State.Status = "running"
+	c.State.Pid = f.nextPid
+	f.nextPid++
+	c.State.ExitCode = 0
+	c.State.StartedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	c.State.FinishedAt = "0001-01-01T00:00:00Z"
+	f.log(c.ID, stdcopy.Stdout, "Starting "+strings.TrimSpace(c.Path+" "+strings.Join(c.Args, " ")))
+	f.publish(c, events.ActionStart, nil)
+}
+
+// stop moves a container to the exited state. Callers hold the lock.
+func (f *FakeEngine) stop(c *types.ContainerJSON, signal string) {
+	if signal == "" {
+		signal = "SIGTERM"
+	}
+	exitCode := 0
+	if number := signalNumber(signal); number != 15 {
+		exitCode = 128 + number
+	}
+	f.publish(c, events.ActionKill, map[string]string{"signal": strconv.Itoa(signalNumber(signal))})
+	f.log(c.ID, stdcopy.Stderr, fmt.Sprintf("Received signal %d, exiting", signalNumber(signal)))
+
+	c.State.Running = false
+	c.State.Paused = false
+	c.State.Status = "exited"
+	c.State.Pid = 0
+	c.State.ExitCode = exitCode
+	c.State.FinishedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	f.publish(c, events.ActionDie, map[string]string{"exitCode": strconv.Itoa(exitCode)})
+}
+
+// remove deletes a stopped container, and its anonymous volumes with volumes. Callers hold the lock.
+func (f *FakeEngine) remove(c *types.ContainerJSON, volumes bool) {
+	delete(f.containers, c.ID)
+	delete(f.logs, c.ID)
+	delete(f.stats, c.ID)
+	delete(f.sizes, c.ID)
+	f.publish(c, events.ActionDestroy, nil)
+	if !volumes {
+		return
+	}
+	for _, m := range c.Mounts {
+		if v, ok := f.volumes[m.Name]; ok && isAnonymousVolume(v) && len(f.volumeContainers(v.Name)) == 0 {
+			f.removeVolume(v)
+		}
+	}
+}
+
+// sample advances the counters of a running container and returns its stats. Callers hold the lock.
+func (f *FakeEngine) sample(c *types.ContainerJSON) container.StatsResponse {
+	const cpus, memoryLimit = 4, 512 * 1024 * 1024
+
+	now := time.Now()
+	stats := container.StatsResponse{Name: c.Name, ID: c.ID}
+	stats.Read = now
+	if !c.State.Running {
+		return stats
+	}
+
+	counters, ok := f.stats[c.ID]
+	if !ok {
+		counters = &fakeStats{read: now.Add(-time.Second)}
+		f.stats[c.ID] = counters
+	}
+	stats.PreRead = counters.read
+	stats.PreCPUStats = container.CPUStats{
+		CPUUsage:    container.CPUUsage{TotalUsage: counters.cpu},
+		SystemUsage: counters.system,
+		OnlineCPUs:  cpus,
+	}
+
+	elapsed := uint64(now.Sub(counters.read).Nanoseconds())
+	counters.read = now
+	counters.system += elapsed * cpus
+	if !c.State.Paused {
+		counters.cpu += uint64(float64(elapsed) * (0.01 + mathrand.Float64()*0.09))
+		counters.rx += uint64(mathrand.Intn(64 * 1024))
+		counters.tx += uint64(mathrand.Intn(32 * 1024))
+		counters.blockRead += uint64(mathrand.Intn(16 * 1024))
+		counters.blockWrite += uint64(mathrand.Intn(8 * 1024))
+	}
+
+	stats.CPUStats = container.CPUStats{
+		CPUUsage:    container.CPUUsage{TotalUsage: counters.cpu},
+		SystemUsage: counters.system,
+		OnlineCPUs:  cpus,
+	}
+	stats.MemoryStats = container.MemoryStats{
+		Usage: uint64(40+mathrand.Intn(20)) * 1024 * 1024,
+		Limit: memoryLimit,
+		Stats: map[string]uint64{"inactive_file": 4 * 1024 * 1024},
+	}
+	stats.PidsStats = container.PidsStats{Current: 2}
+	stats.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: counters.rx, TxBytes: counters.tx},
+	}
+	stats.BlkioStats = container.BlkioStats{
+		IoServiceBytesRecursive: []container.BlkioStatEntry{
+			{Major: 8, Op: "read", Value: counters.blockRead},
+			{Major: 8, Op: "write", Value: counters.blockWrite},
+		},
+	}
+	return stats
+}
+
+// log records a line of container output. Callers hold the lock.
+func (f *FakeEngine) log(id string, stream stdcopy.StdType, text string) {
+	f.logs[id] = append(f.logs[id], fakeLog{time: time.Now(), stream: stream, text: text})
+}
+
+// publish sends a container event to subscribers, dropping it for slow ones.
+func (f *FakeEngine) publish(c *types.ContainerJSON, action events.Action, extra map[string]string) {
+	attributes := map[string]string{
+		"name":  strings.TrimPrefix(c.Name, "/"),
+		"image": c.Config.Image,
+	}
+	for k, v := range c.Config.Labels {
+		attributes[k] = v
+	}
+	for k, v := range extra {
+		attributes[k] = v
+	}
+
+	f.broadcast(events.Message{
+		Type:   events.ContainerEventType,
+		Action: action,
+		Actor:  events.Actor{ID: c.ID, Attributes: attributes},
+	})
+}
+
+// broadcast timestamps a message and sends it to subscribers. Callers hold the lock.
+func (f *FakeEngine) broadcast(msg events.Message) {
+	now := time.Now()
+	msg.Scope = "local"
+	msg.Time = now.Unix()
+	msg.TimeNano = now.UnixNano()
+	for sub := range f.subscribers {
+		select {
+		case sub <- msg:
+		default:
+		}
+	}
+}
+
+// endpoints attaches a new container to its network. Callers hold the lock.
+func (f *FakeEngine) endpoints(hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig) (map[string]*network.EndpointSettings, error) {
+	networks := make(map[string]*network.EndpointSettings)
+	if networkingConfig != nil {
+		for name, endpoint := range networkingConfig.EndpointsConfig {
+			settings := network.EndpointSettings{}
+			if endpoint != nil {
+				settings = *endpoint
+			}
+			networks[name] = &settings
+		}
+	}
+	if len(networks) == 0 {
+		name := string(hostConfig.NetworkMode)
+		if name == "" || name == "default" {
+			name = "bridge"
+		}
+		if name == "none" || name == "host" {
+			return networks, nil
+		}
+		networks[name] = &network.EndpointSettings{}
+	}
+	for name, settings := range networks {
+		n, err := f.lookupNetwork(name)
+		if err != nil {
+			return nil, err
+		}
+		f.attach(n, settings)
+	}
+	return networks, nil
+}
+
+// summary converts an inspected container to its list form.
+func (f *FakeEngine) summary(c *types.ContainerJSON) types.Container {
+	created, _ := time.Parse(time.RFC3339Nano, c.Created)
+
+	summary := types.Container{
+		ID:      c.ID,
+		Names:   []string{c.Name},
+		Image:   c.Config.Image,
+		ImageID: c.Image,
+		Command: strings.TrimSpace(c.Path + " " + strings.Join(c.Args, " ")),
+		Created: created.Unix(),
+		Ports:   fakePorts(c.HostConfig),
+		Labels:  c.Config.Labels,
+		State:   c.State.Status,
+		Status:  fakeStatus(c.State),
+		Mounts:  c.Mounts,
+		NetworkSettings: &types.SummaryNetworkSettings{
+			Networks: c.NetworkSettings.Networks,
+		},
+	}
+	summary.HostConfig.NetworkMode = string(c.HostConfig.NetworkMode)
+	return summary
+}
+
+// cloneContainer copies the mutable parts of a container.
+func cloneContainer(c *types.ContainerJSON) types.ContainerJSON {
+	base := *c.ContainerJSONBase
+	state := *c.State
+	base.State = &state
+
+	settings := *c.NetworkSettings
+	settings.Networks = make(map[string]*network.EndpointSettings, len(c.NetworkSettings.Networks))
+	for name, endpoint := range c.NetworkSettings.Networks {
+		copied := *endpoint
+		settings.Networks[name] = &copied
+	}
+
+	clone := *c
+	clone.ContainerJSONBase = &base
+	clone.NetworkSettings = &settings
+	return clone
+}
+
+// matchContainer applies the daemon's list filters to a container summary.
+func matchContainer(args filters.Args, c *types.Container) bool {
+	if args.Contains("status") && !args.ExactMatch("status", c.State) {
+		return false
+	}
+	if args.Contains("id") && !args.Match("id", c.ID) {
+		return false
+	}
+	if args.Contains("name") && !args.Match("name", strings.TrimPrefix(c.Names[0], "/")) {
+		return false
+	}
+	if args.Contains("ancestor") && !args.ExactMatch("ancestor", c.Image) && !args.ExactMatch("ancestor", c.ImageID) &&
+		!args.ExactMatch("ancestor", strings.TrimSuffix(c.Image, ":latest")) {
+		return false
+	}
+	if args.Contains("label") && !args.MatchKVList("label", c.Labels) {
+		return false
+	}
+	if args.Contains("network") {
+		found := false
+		if c.NetworkSettings != nil {
+			for name := range c.NetworkSettings.Networks {
+				found = found || args.ExactMatch("network", name)
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchEvent applies the daemon's event filters to a message.
+func matchEvent(args filters.Args, msg events.Message) bool {
+	if args.Contains("type") && !args.ExactMatch("type", string(msg.Type)) {
+		return false
+	}
+	// Like the daemon, "health_status: healthy" matches the health_status action
+	action, _, _ := strings.Cut(string(msg.Action), ":")
+	if args.Contains("event") && !args.ExactMatch("event", string(msg.Action)) && !args.ExactMatch("event", action) {
+		return false
+	}
+	if args.Contains("container") && msg.Type == events.ContainerEventType &&
+		!args.ExactMatch("container", msg.Actor.ID) && !args.ExactMatch("container", msg.Actor.Attributes["name"]) {
+		return false
+	}
+	if args.Contains("image") && !args.ExactMatch("image", msg.Actor.Attributes["image"]) {
+		return false
+	}
+	if args.Contains("label") && !args.MatchKVList("label", msg.Actor.Attributes) {
+		return false
+	}
+	return true
+}
+
+func fakeStatus(state *types.ContainerState) string {
+	switch state.Status {
+	case "running", "paused":
+		started, _ := time.Parse(time.RFC3339Nano, state.StartedAt)
+		status := "Up " + units.HumanDuration(time.Since(started))
+		if state.Paused {
+			status += " (Paused)"
+		}
+		return status
+	case "exited":
+		finished, _ := time.Parse(time.RFC3339Nano, state.FinishedAt)
+		return fmt.Sprintf("Exited (%d) %s ago", state.ExitCode, units.HumanDuration(time.Since(finished)))
+	default:
+		return "Created"
+	}
+}
+
+func fakePorts(hostConfig *container.HostConfig) []types.Port {
+	ports := []types.Port{}
+	for port, bindings := range hostConfig.PortBindings {
+		for _, binding := range bindings {
+			public, _ := strconv.Atoi(binding.HostPort)
+			ports = append(ports, types.Port{
+				IP:          binding.HostIP,
+				PrivatePort: uint16(port.Int()),
+				PublicPort:  uint16(public),
+				Type:        port.Proto(),
+			})
+		}
+	}
+	return ports
+}
+
+func fakeMounts(hostConfig *container.HostConfig) []types.MountPoint {
+	mounts := []types.MountPoint{}
+	for _, bind := range hostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) < 2 {
+			continue
+		}
+		m := types.MountPoint{Source: parts[0], Destination: parts[1], RW: true}
+		if len(parts) > 2 {
+			m.Mode = parts[2]
+			m.RW = !strings.Contains(parts[2], "ro")
+		}
+		if strings.HasPrefix(parts[0], "/") {
+			m.Type = mount.TypeBind
+		} else {
+			m.Type = mount.TypeVolume
+			m.Name = parts[0]
+			m.Driver = "local"
+			m.Source = "/var/lib/docker/volumes/" + parts[0] + "/_data"
+		}
+		mounts = append(mounts, m)
+	}
+	for _, mnt := range hostConfig.Mounts {
+		m := types.MountPoint{Type: mnt.Type, Source: mnt.Source, Destination: mnt.Target, RW: !mnt.ReadOnly}
+		if mnt.Type == mount.TypeVolume {
+			m.Name = mnt.Source
+			m.Driver = "local"
+			m.Source = "/var/lib/docker/volumes/" + mnt.Source + "/_data"
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts
+}
+
+func signalNumber(signal string) int {
+	signal = strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if n, err := strconv.Atoi(signal); err == nil {
+		return n
+	}
+	numbers := map[string]int{"HUP": 1, "INT": 2, "QUIT": 3, "KILL": 9, "USR1": 10, "USR2": 12, "TERM": 15}
+	if n, ok := numbers[signal]; ok {
+		return n
+	}
+	return 9
+}
+
+func randomID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func imageDigest(ref string) string {
+	sum := sha256.Sum256([]byte(ref))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// fakeExec is an exec instance of the fake engine. Its commands are run by a tiny
+// interpreter knowing echo, hostname, pwd, whoami, env, ls, sleep, kill, true, false,
+// exit and exec, with $$, "$@" and ||. Like the real ones, sleep ignores the end of its
+// streams and only a kill stops it. The daemon reports the pids of the host, the
+// commands in the container only know the pids of its namespace.
+type fakeExec struct {
+	id            string
+	containerID   string
+	config        container.ExecOptions
+	started       bool
+	running       bool
+	exitCode      int
+	pid           int
+	height, width uint
+	killed        chan struct{}
+}
+
+// ContainerExecCreate prepares a command to run in a running container.
+func (f *FakeEngine) ContainerExecCreate(_ context.Context, ref string, options container.ExecOptions) (types.IDResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.lookup(ref)
+	if err != nil {
+		return types.IDResponse{}, err
+	}
+	if !c.State.Running {
+		return types.IDResponse{}, errdefs.Conflict(fmt.Errorf("container %s is not running", c.ID))
+	}
+	if len(options.Cmd) == 0 {
+		return types.IDResponse{}, errdefs.InvalidParameter(fmt.Errorf("no exec command specified"))
+	}
+
+	exec := &fakeExec{id: randomID(), containerID: c.ID, config: options, killed: make(chan struct{})}
+	if options.ConsoleSize != nil {
+		exec.height, exec.width = options.ConsoleSize[0], options.ConsoleSize[1]
+	}
+	f.execs[exec.id] = exec
+	f.publish(c, events.Action("exec_create: "+strings.Join(options.Cmd, " ")), map[string]string{"execID": exec.id})
+	return types.IDResponse{ID: exec.id}, nil
+}
+
+// ContainerExecAttach starts an exec instance and returns its streams, multiplexed unless it has a TTY.
+func (f *FakeEngine) ContainerExecAttach(_ context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	exec, env, err := f.startExec(execID, config.ConsoleSize)
+	if err != nil {
+		return types.HijackedResponse{}, err
+	}
+	client, server := net.Pipe()
+	go f.runExec(exec, env, server)
+
+	mediaType := types.MediaTypeMultiplexedStream
+	if exec.config.Tty {
+		mediaType = types.MediaTypeRawStream
+	}
+	return types.NewHijackedResponse(client, mediaType), nil
+}
+
+// ContainerExecStart starts an exec instance detached from its streams.
+func (f *FakeEngine) ContainerExecStart(_ context.Context, execID string, config container.ExecStartOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	exec, env, err := f.startExec(execID, config.ConsoleSize)
+	if err != nil {
+		return err
+	}
+	go f.runExec(exec, env, detachedConn{})
+	return nil
+}
+
+// startExec marks an exec instance as running and returns it with its environment.
+func (f *FakeEngine) startExec(execID string, consoleSize *[2]uint) (*fakeExec, []string, error) {
+	exec, ok := f.execs[execID]
+	if !ok {
+		return nil, nil, errdefs.NotFound(fmt.Errorf("No such exec instance: %s", execID))
+	}
+	if exec.started {
+		return nil, nil, errdefs.Conflict(fmt.Errorf("exec %s has already been started", execID))
+	}
+	c, ok := f.containers[exec.containerID]
+	if !ok || !c.State.Running {
+		return nil, nil, errdefs.Conflict(fmt.Errorf("container %s is not running", exec.containerID))
+	}
+	exec.started, exec.running = true, true
+	exec.pid = f.nextPid
+	f.nextPid++
+	if consoleSize != nil {
+		exec.height, exec.width = consoleSize[0], consoleSize[1]
+	}
+	f.publish(c, events.Action("exec_start: "+strings.Join(exec.config.Cmd, " ")), map[string]string{"execID": exec.id})
+	return exec, append(append([]string{}, c.Config.Env...), exec.config.Env...), nil
+}
+
+// detachedConn are the streams of a detached exec instance: no input, outputs dropped.
+type detachedConn struct{}
+
+func (detachedConn) Read([]byte) (int, error)    { return 0, io.EOF }
+func (detachedConn) Write(p []byte) (int, error) { return len(p), nil }
+func (detachedConn) Close() error                { return nil }
+
+// ContainerExecResize changes the size of the TTY of a running exec instance.
+func (f *FakeEngine) ContainerExecResize(_ context.Context, execID string, options container.ResizeOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	exec, ok := f.execs[execID]
+	if !ok {
+		return errdefs.NotFound(fmt.Errorf("No such exec instance: %s", execID))
+	}
+	if !exec.running {
+		return errdefs.Conflict(fmt.Errorf("exec %s is not running", execID))
+	}
+	exec.height, exec.width = options.Height, options.Width
+	return nil
+}
+
+// fakeHostPids is the offset of the host pids of the exec instances from their pids in the containers.
+const fakeHostPids = 40000
+
+// ContainerExecInspect returns the state of an exec instance.
+func (f *FakeEngine) ContainerExecInspect(_ context.Context, execID string) (container.ExecInspect, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	exec, ok := f.execs[execID]
+	if !ok {
+		return container.ExecInspect{}, errdefs.NotFound(fmt.Errorf("No such exec instance: %s", execID))
+	}
+	return container.ExecInspect{
+		ExecID:      exec.id,
+		ContainerID: exec.containerID,
+		Running:     exec.running,
+		ExitCode:    exec.exitCode,
+		Pid:         exec.pid + fakeHostPids,
+	}, nil
+}
+
+// runExec interprets the command of an exec instance on conn. Shells without -c read
+// their commands from the input, until exit or the end of the input.
+func (f *FakeEngine) runExec(exec *fakeExec, env []string, conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	var stdout, stderr io.Writer = conn, conn
+	newline := "\n"
+	if exec.config.Tty {
+		newline = "\r\n"
+	} else {
+		stdout, stderr = stdcopy.NewStdWriter(conn, stdcopy.Stdout), stdcopy.NewStdWriter(conn, stdcopy.Stderr)
+	}
+	sh := &fakeShell{engine: f, exec: exec, env: env, stdout: stdout, stderr: stderr, newline: newline}
+
+	// The input is read until the client closes it, which interrupts the commands
+	lines := make(chan string)
+	closed := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(closed)
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			select {
+			case lines <- strings.TrimRight(scanner.Text(), "\r"):
+			case <-done:
+				return
+			}
+		}
+	}()
+	sh.closed, sh.lines = closed, lines
+
+	code := sh.command(exec.config.Cmd)
+
+	f.mu.Lock()
+	exec.running = false
+	exec.exitCode = code
+	if c, ok := f.containers[exec.containerID]; ok {
+		f.publish(c, "exec_die", map[string]string{"execID": exec.id, "exitCode": strconv.Itoa(code)})
+	}
+	f.mu.Unlock()
+}
+
+func isShell(cmd string) bool {
+	switch cmd {
+	case "sh", "/bin/sh", "bash", "/bin/bash", "ash", "/bin/ash":
+		return true
+	}
+	return false
+}
+
+// fakeShell runs the commands of a fake exec instance.
+type fakeShell struct {
+	engine         *FakeEngine
+	exec           *fakeExec
+	env            []string
+	stdout, stderr io.Writer
+	newline        string
+	closed         <-chan struct{}
+	lines          <-chan string
+	// positional are the arguments of a shell script, "$@"
+	positional []string
+}
+
+// command runs a command line: shells without -c read their commands from the input,
+// until exit or the end of the input.
+func (sh *fakeShell) command(cmd []string) int {
+	switch {
+	case isShell(cmd[0]) && len(cmd) > 2 && cmd[1] == "-c":
+		if len(cmd) > 3 {
+			sh.positional = cmd[4:]
+		}
+		code, _ := sh.script(cmd[2])
+		return code
+	case isShell(cmd[0]):
+		return sh.interactive(sh.lines)
+	default:
+		code, _ := sh.run(cmd)
+		return code
+	}
+}
+
+// interactive runs the lines of the input, with a prompt and an echo on a TTY.
+func (sh *fakeShell) interactive(lines <-chan string) int {
+	code := 0
+	for {
+		if sh.exec.config.Tty {
+			fmt.Fprint(sh.stdout, "/ # ")
+		}
+		select {
+		case line := <-lines:
+			if sh.exec.config.Tty {
+				fmt.Fprint(sh.stdout, line+sh.newline)
+			}
+			var exit bool
+			if code, exit = sh.script(line); exit {
+				return code
+			}
+		case <-sh.closed:
+			return code
+		case <-sh.exec.killed:
+			return 137
+		}
+	}
+}
+
+// script runs commands separated by semicolons, returning the last exit code. A command
+// failing runs the alternative following ||, and the redirections are ignored.
+func (sh *fakeShell) script(script string) (int, bool) {
+	code := 0
+	for _, command := range strings.Split(script, ";") {
+		for _, alternative := range strings.Split(command, "||") {
+			args := []string{}
+			for _, arg := range strings.Fields(alternative) {
+				if !strings.Contains(arg, ">") {
+					args = append(args, arg)
+				}
+			}
+			if len(args) == 0 {
+				continue
+			}
+			var exit bool
+			if code, exit = sh.run(args); exit {
+				return code, true
+			}
+			if code == 0 {
+				break
+			}
+		}
+	}
+	return code, false
+}
+
+// run runs a command and tells if it was exit.
+func (sh *fakeShell) run(args []string) (int, bool) {
+	print := func(s string) { fmt.Fprint(sh.stdout, s+sh.newline) }
+	for i, arg := range args {
+		if arg == "$$" {
+			args[i] = strconv.Itoa(sh.exec.pid)
+		}
+	}
+
+	switch args[0] {
+	case "exec":
+		if len(args) == 2 && strings.Trim(args[1], `"`) == "$@" {
+			args = append([]string{"exec"}, sh.positional...)
+		}
+		if len(args) < 2 {
+			return 0, true
+		}
+		return sh.command(args[1:]), true
+	case "exit":
+		code := 0
+		if len(args) > 1 {
+			code, _ = strconv.Atoi(args[1])
+		}
+		return code, true
+	case "echo":
+		print(strings.Join(args[1:], " "))
+	case "hostname":
+		print(sh.exec.containerID[:12])
+	case "pwd":
+		dir := sh.exec.config.WorkingDir
+		if dir == "" {
+			dir = "/"
+		}
+		print(dir)
+	case "whoami":
+		user := sh.exec.config.User
+		if user == "" {
+			user = "root"
+		}
+		print(user)
+	case "env":
+		env := append([]string{}, sh.env...)
+		sort.Strings(env)
+		for _, e := range env {
+			print(e)
+		}
+	case "ls":
+		print("bin  dev  etc  home  lib  proc  root  sys  tmp  usr  var")
+	case "true":
+	case "false":
+		return 1, false
+	case "sleep":
+		seconds := 1.0
+		if len(args) > 1 {
+			seconds, _ = strconv.ParseFloat(args[1], 64)
+		}
+		select {
+		case <-time.After(time.Duration(seconds * float64(time.Second))):
+		case <-sh.exec.killed:
+			return 137, true
+		}
+	case "kill":
+		if len(args) < 2 {
+			fmt.Fprint(sh.stderr, "kill: usage: kill [-9] pid"+sh.newline)
+			return 1, false
+		}
+		// A negative pid is a process group, the one of an exec leads its own
+		pid, _ := strconv.Atoi(strings.TrimPrefix(args[len(args)-1], "-"))
+		if !sh.engine.killExec(sh.exec.containerID, pid) {
+			fmt.Fprint(sh.stderr, "kill: can't kill pid "+args[len(args)-1]+": No such process"+sh.newline)
+			return 1, false
+		}
+	default:
+		fmt.Fprint(sh.stderr, "sh: "+args[0]+": not found"+sh.newline)
+		return 127, false
+	}
+	return 0, false
+}
+
+// killExec kills the running exec instance of a container given its pid.
+func (f *FakeEngine) killExec(containerID string, pid int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, exec := range f.execs {
+		if exec.containerID == containerID && exec.pid == pid && exec.running {
+			select {
+			case <-exec.killed:
+			default:
+				close(exec.killed)
+			}
+			return true
+		}
+	}
+	return false
+}
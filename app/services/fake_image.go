@@ -0,0 +1,365 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// ImageInspectWithRaw returns the image matching a reference, an ID or a unique ID prefix.
+func (f *FakeEngine) ImageInspectWithRaw(_ context.Context, ref string) (types.ImageInspect, []byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	img, err := f.lookupImage(ref)
+	if err != nil {
+		return types.ImageInspect{}, nil, err
+	}
+	inspect := types.ImageInspect{
+		ID:           img.ID,
+		RepoTags:     img.RepoTags,
+		RepoDigests:  img.RepoDigests,
+		Created:      time.Unix(img.Created, 0).UTC().Format(time.RFC3339Nano),
+		Config:       &container.Config{Labels: img.Labels},
+		Architecture: "amd64",
+		Os:           "linux",
+		Size:         img.Size,
+	}
+	raw, err := json.Marshal(inspect)
+	return inspect, raw, err
+}
+
+// ImagePull simulates the download of an image and streams the daemon's progress messages.
+func (f *FakeEngine) ImagePull(ctx context.Context, ref string, _ image.PullOptions) (io.ReadCloser, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+	named = reference.TagNameOnly(named)
+	familiar := reference.FamiliarString(named)
+	tag := named.(reference.Tagged).Tag()
+
+	reader, writer := io.Pipe()
+	go func() {
+		encoder := json.NewEncoder(writer)
+		messages := []jsonmessage.JSONMessage{{Status: "Pulling from " + reference.Path(named), ID: tag}}
+		layers := []string{randomID()[:12], randomID()[:12]}
+		for _, layer := range layers {
+			messages = append(messages, jsonmessage.JSONMessage{Status: "Pulling fs layer", ID: layer})
+		}
+		for _, layer := range layers {
+			const total = 3 * 1024 * 1024
+			for current := int64(total / 3); current <= total; current += total / 3 {
+				messages = append(messages, jsonmessage.JSONMessage{
+					Status:   "Downloading",
+					ID:       layer,
+					Progress: &jsonmessage.JSONProgress{Current: current, Total: total},
+				})
+			}
+			messages = append(messages, jsonmessage.JSONMessage{Status: "Pull complete", ID: layer})
+		}
+		messages = append(messages,
+			jsonmessage.JSONMessage{Status: "Digest: " + imageDigest(familiar+"@digest")},
+			jsonmessage.JSONMessage{Status: "Status: Downloaded newer image for " + familiar},
+		)
+
+		for _, msg := range messages {
+			select {
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+			if err := encoder.Encode(msg); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+
+		f.mu.Lock()
+		img := f.addImage(familiar, 64*1024*1024)
+		f.publishImage(img.ID, events.ActionPull, familiar)
+		f.mu.Unlock()
+		writer.Close()
+	}()
+
+	return reader, nil
+}
+
+// ImageList lists the images matching the dangling, label and reference filters, newest first.
+// Fake images have no parent, options.All makes no difference.
+func (f *FakeEngine) ImageList(_ context.Context, options image.ListOptions) ([]image.Summary, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	list := make([]image.Summary, 0, len(f.images))
+	for _, img := range f.images {
+		summary := *img
+		summary.RepoTags = append([]string{}, img.RepoTags...)
+		summary.RepoDigests = append([]string{}, img.RepoDigests...)
+		if options.ContainerCount {
+			summary.Containers = int64(len(f.imageContainers(img.ID)))
+		}
+		if !matchImage(options.Filters, &summary) {
+			continue
+		}
+		list = append(list, summary)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created == list[j].Created {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Created > list[j].Created
+	})
+	return list, nil
+}
+
+// ImageTag adds the target tag to an image, moving it from the image holding it.
+func (f *FakeEngine) ImageTag(_ context.Context, source, target string) error {
+	named, err := reference.ParseNormalizedNamed(target)
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return errdefs.InvalidParameter(fmt.Errorf("refusing to create a tag with a digest reference"))
+	}
+	tag := reference.FamiliarString(reference.TagNameOnly(named))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	img, err := f.lookupImage(source)
+	if err != nil {
+		return err
+	}
+	for _, other := range f.images {
+		if other != img {
+			other.RepoTags = removeString(other.RepoTags, tag)
+		}
+	}
+	if !functions.Contains(img.RepoTags, tag) {
+		img.RepoTags = append(img.RepoTags, tag)
+	}
+	f.publishImage(img.ID, events.ActionTag, tag)
+	return nil
+}
+
+// ImageRemove untags an image, and deletes it once it has no more tags. An image used by
+// a container is only deleted with options.Force, never when the container is running.
+// Fake images have no parent, options.PruneChildren makes no difference.
+func (f *FakeEngine) ImageRemove(_ context.Context, ref string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	img, err := f.lookupImage(ref)
+	if err != nil {
+		return nil, err
+	}
+	shortID := strings.TrimPrefix(img.ID, "sha256:")[:12]
+
+	// Removing one of the tags of an image only untags it
+	tag := ""
+	if named, err := reference.ParseNormalizedNamed(ref); err == nil {
+		tag = reference.FamiliarString(reference.TagNameOnly(named))
+	}
+	if functions.Contains(img.RepoTags, tag) && len(img.RepoTags) > 1 {
+		img.RepoTags = removeString(img.RepoTags, tag)
+		f.publishImage(img.ID, events.ActionUnTag, tag)
+		return []image.DeleteResponse{{Untagged: tag}}, nil
+	}
+	if len(img.RepoTags) > 1 && !options.Force {
+		return nil, errdefs.Conflict(fmt.Errorf("conflict: unable to delete %s (must be forced) - image is referenced in multiple repositories", shortID))
+	}
+
+	for _, id := range f.imageContainers(img.ID) {
+		if f.containers[id].State.Running {
+			return nil, errdefs.Conflict(fmt.Errorf("conflict: unable to delete %s (cannot be forced) - image is being used by running container %s", shortID, id[:12]))
+		}
+		if !options.Force {
+			return nil, errdefs.Conflict(fmt.Errorf("conflict: unable to delete %s (must be forced) - image is being used by stopped container %s", shortID, id[:12]))
+		}
+	}
+
+	deleted := []image.DeleteResponse{}
+	for _, tag := range img.RepoTags {
+		deleted = append(deleted, image.DeleteResponse{Untagged: tag})
+		f.publishImage(img.ID, events.ActionUnTag, tag)
+	}
+	for _, digest := range img.RepoDigests {
+		deleted = append(deleted, image.DeleteResponse{Untagged: digest})
+	}
+	deleted = append(deleted, image.DeleteResponse{Deleted: img.ID})
+	delete(f.images, img.ID)
+	f.publishImage(img.ID, events.ActionDelete, img.ID)
+	return deleted, nil
+}
+
+// ImageHistory returns the layers of an image, newest first: a base system, a package
+// install and the command of the image.
+func (f *FakeEngine) ImageHistory(_ context.Context, ref string) ([]image.HistoryResponseItem, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	img, err := f.lookupImage(ref)
+	if err != nil {
+		return nil, err
+	}
+	base := img.Size / 3
+	return []image.HistoryResponseItem{
+		{
+			ID:        img.ID,
+			Created:   img.Created,
+			CreatedBy: `CMD ["/bin/sh"]`,
+			Tags:      append([]string{}, img.RepoTags...),
+			Comment:   "buildkit.dockerfile.v0",
+		},
+		{
+			ID:        "<missing>",
+			Created:   img.Created,
+			CreatedBy: "RUN /bin/sh -c apt-get update && apt-get install -y --no-install-recommends ca-certificates # buildkit",
+			Size:      img.Size - base,
+			Comment:   "buildkit.dockerfile.v0",
+		},
+		{
+			ID:        "<missing>",
+			Created:   img.Created - 7*24*3600,
+			CreatedBy: "/bin/sh -c #(nop) ADD file:" + strings.TrimPrefix(imageDigest(img.ID+"@base"), "sha256:") + " in / ",
+			Size:      base,
+		},
+	}, nil
+}
+
+// imageContainers returns the IDs of the containers created from an image. Callers hold the lock.
+func (f *FakeEngine) imageContainers(id string) []string {
+	ids := []string{}
+	for _, c := range f.containers {
+		if c.Image == id {
+			ids = append(ids, c.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// addImage registers an image, moving its tag from an older image. Callers hold the lock.
+func (f *FakeEngine) addImage(ref string, size int64) *image.Summary {
+	id := imageDigest(ref)
+	name := ref
+	if i := strings.LastIndex(ref, ":"); i > 0 {
+		name = ref[:i]
+	}
+	if img, ok := f.images[id]; ok {
+		return img
+	}
+	img := &image.Summary{
+		ID:          id,
+		RepoTags:    []string{ref},
+		RepoDigests: []string{name + "@" + imageDigest(ref+"@digest")},
+		Created:     time.Now().Add(-14 * 24 * time.Hour).Unix(),
+		Labels:      map[string]string{},
+		ParentID:    "",
+		Size:        size,
+		Containers:  -1,
+		SharedSize:  -1,
+	}
+	f.images[id] = img
+	return img
+}
+
+// lookupImage resolves an image reference, an ID or a unique ID prefix. Callers hold the lock.
+func (f *FakeEngine) lookupImage(ref string) (*image.Summary, error) {
+	if named, err := reference.ParseNormalizedNamed(ref); err == nil {
+		familiar := reference.FamiliarString(reference.TagNameOnly(named))
+		for _, img := range f.images {
+			for _, tag := range img.RepoTags {
+				if tag == familiar {
+					return img, nil
+				}
+			}
+		}
+	}
+
+	id := ref
+	if !strings.HasPrefix(id, "sha256:") {
+		id = "sha256:" + id
+	}
+	var found *image.Summary
+	if len(ref) > 0 {
+		for imageID, img := range f.images {
+			if strings.HasPrefix(imageID, id) {
+				if found != nil {
+					return nil, errdefs.InvalidParameter(fmt.Errorf("multiple IDs found with provided prefix: %s", ref))
+				}
+				found = img
+			}
+		}
+	}
+	if found == nil {
+		return nil, errdefs.NotFound(fmt.Errorf("No such image: %s", ref))
+	}
+	return found, nil
+}
+
+// publishImage sends an image event to subscribers. Callers hold the lock.
+func (f *FakeEngine) publishImage(id string, action events.Action, name string) {
+	f.broadcast(events.Message{
+		Type:   events.ImageEventType,
+		Action: action,
+		Actor:  events.Actor{ID: id, Attributes: map[string]string{"name": name}},
+	})
+}
+
+// matchImage tells if an image matches the dangling, label and reference filters of the daemon.
+func matchImage(args filters.Args, img *image.Summary) bool {
+	if args.Contains("dangling") && !args.ExactMatch("dangling", strconv.FormatBool(len(img.RepoTags) == 0)) {
+		return false
+	}
+	if args.Contains("label") && !args.MatchKVList("label", img.Labels) {
+		return false
+	}
+	if args.Contains("reference") {
+		found := false
+		for _, pattern := range args.Get("reference") {
+			for _, tag := range img.RepoTags {
+				// A reference without tag matches every tag of the repository
+				repository := tag[:strings.LastIndex(tag, ":")]
+				if ok, _ := path.Match(pattern, tag); ok {
+					found = true
+				} else if ok, _ := path.Match(pattern, repository); ok {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func removeString(list []string, s string) []string {
+	kept := []string{}
+	for _, item := range list {
+		if item != s {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+)
+
+// NetworkList lists the networks matching the dangling, driver, id, label, name and type filters, by name.
+func (f *FakeEngine) NetworkList(_ context.Context, options network.ListOptions) ([]network.Summary, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	list := make([]network.Summary, 0, len(f.networks))
+	for _, n := range f.networks {
+		if !f.matchNetwork(options.Filters, n) {
+			continue
+		}
+		summary := *n
+		// Like the daemon, the list does not hold the containers
+		summary.Containers = map[string]network.EndpointResource{}
+		list = append(list, summary)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// NetworkInspect returns the network matching a name, an ID or a unique ID prefix, with its containers.
+func (f *FakeEngine) NetworkInspect(_ context.Context, ref string, _ network.InspectOptions) (network.Inspect, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	n, err := f.lookupNetwork(ref)
+	if err != nil {
+		return network.Inspect{}, err
+	}
+	inspect := *n
+	inspect.Containers = make(map[string]network.EndpointResource)
+	for _, id := range f.networkContainers(n.Name) {
+		c := f.containers[id]
+		endpoint := c.NetworkSettings.Networks[n.Name]
+		resource := network.EndpointResource{
+			Name:       strings.TrimPrefix(c.Name, "/"),
+			EndpointID: endpoint.EndpointID,
+			MacAddress: endpoint.MacAddress,
+		}
+		if endpoint.IPAddress != "" {
+			resource.IPv4Address = fmt.Sprintf("%s/%d", endpoint.IPAddress, endpoint.IPPrefixLen)
+		}
+		inspect.Containers[id] = resource
+	}
+	return inspect, nil
+}
+
+// NetworkCreate registers a network, on the subnet of options.IPAM when set.
+func (f *FakeEngine) NetworkCreate(_ context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, n := range f.networks {
+		if n.Name == name {
+			return network.CreateResponse{}, errdefs.Conflict(fmt.Errorf("network with name %s already exists", name))
+		}
+	}
+	driver := options.Driver
+	if driver == "" {
+		driver = "bridge"
+	}
+	if driver == "host" || driver == "null" {
+		return network.CreateResponse{}, errdefs.Forbidden(fmt.Errorf("only one instance of %q network is allowed", driver))
+	}
+
+	var configs []network.IPAMConfig
+	if options.IPAM != nil {
+		for _, config := range options.IPAM.Config {
+			if !f.subnetFree(config.Subnet) {
+				return network.CreateResponse{}, errdefs.Forbidden(errors.New("invalid pool request: Pool overlaps with other one on this address space"))
+			}
+			// Like the daemon, the gateway defaults to the first address of the subnet
+			if _, ipNet, err := net.ParseCIDR(config.Subnet); err == nil && config.Gateway == "" {
+				if ip := ipNet.IP.To4(); ip != nil {
+					config.Gateway = net.IPv4(ip[0], ip[1], ip[2], ip[3]+1).String()
+				}
+			}
+			configs = append(configs, config)
+		}
+	}
+
+	n := f.addNetwork(name, driver, options.Labels)
+	if len(configs) > 0 {
+		n.IPAM.Config = configs
+	}
+	n.Internal = options.Internal
+	n.Attachable = options.Attachable
+	if options.Options != nil {
+		n.Options = options.Options
+	}
+	return network.CreateResponse{ID: n.ID}, nil
+}
+
+// NetworkRemove deletes a custom network without containers.
+func (f *FakeEngine) NetworkRemove(_ context.Context, ref string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.lookupNetwork(ref)
+	if err != nil {
+		return err
+	}
+	if isPredefinedNetwork(n.Name) {
+		return errdefs.Forbidden(fmt.Errorf("%s is a pre-defined network and cannot be removed", n.Name))
+	}
+	if len(f.networkContainers(n.Name)) > 0 {
+		return errdefs.Forbidden(fmt.Errorf("error while removing network: network %s id %s has active endpoints", n.Name, n.ID))
+	}
+	f.removeNetwork(n)
+	return nil
+}
+
+// NetworkConnect attaches a container to a network.
+func (f *FakeEngine) NetworkConnect(_ context.Context, ref, containerRef string, config *network.EndpointSettings) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.lookupNetwork(ref)
+	if err != nil {
+		return err
+	}
+	c, err := f.lookup(containerRef)
+	if err != nil {
+		return err
+	}
+	if mode := c.HostConfig.NetworkMode; mode == "host" || mode == "none" || n.Driver == "host" || n.Driver == "null" {
+		return errdefs.Forbidden(fmt.Errorf("container sharing network namespace with another container or host cannot be connected to any other network"))
+	}
+	if _, ok := c.NetworkSettings.Networks[n.Name]; ok {
+		return errdefs.Forbidden(fmt.Errorf("endpoint with name %s already exists in network %s", strings.TrimPrefix(c.Name, "/"), n.Name))
+	}
+
+	settings := &network.EndpointSettings{}
+	if config != nil {
+		copied := *config
+		settings = &copied
+	}
+	f.attach(n, settings)
+	c.NetworkSettings.Networks[n.Name] = settings
+	f.publishEndpoint(n, c, events.ActionConnect)
+	return nil
+}
+
+// NetworkDisconnect detaches a container from a network.
+func (f *FakeEngine) NetworkDisconnect(_ context.Context, ref, containerRef string, _ bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.lookupNetwork(ref)
+	if err != nil {
+		return err
+	}
+	c, err := f.lookup(containerRef)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.NetworkSettings.Networks[n.Name]; !ok {
+		return errdefs.Conflict(fmt.Errorf("container %s is not connected to network %s", c.ID[:12], n.Name))
+	}
+	delete(c.NetworkSettings.Networks, n.Name)
+	f.publishEndpoint(n, c, events.ActionDisconnect)
+	return nil
+}
+
+// addNetwork registers a network, on the next free 172.x.0.0/16 subnet but for the host
+// and null drivers. Callers hold the lock.
+func (f *FakeEngine) addNetwork(name, driver string, labels map[string]string) *network.Inspect {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	n := &network.Inspect{
+		Name:       name,
+		ID:         randomID(),
+		Created:    time.Now().UTC(),
+		Scope:      "local",
+		Driver:     driver,
+		IPAM:       network.IPAM{Driver: "default", Options: map[string]string{}, Config: []network.IPAMConfig{}},
+		Containers: map[string]network.EndpointResource{},
+		Options:    map[string]string{},
+		Labels:     labels,
+	}
+	if driver != "host" && driver != "null" {
+		for i := 17; i < 32; i++ {
+			subnet := fmt.Sprintf("172.%d.0.0/16", i)
+			if f.subnetFree(subnet) {
+				n.IPAM.Config = append(n.IPAM.Config, network.IPAMConfig{Subnet: subnet, Gateway: fmt.Sprintf("172.%d.0.1", i)})
+				break
+			}
+		}
+	}
+	f.networks[n.ID] = n
+	if !isPredefinedNetwork(name) {
+		f.publishNetwork(n, events.ActionCreate)
+	}
+	return n
+}
+
+// subnetFree tells if a subnet overlaps none of the networks. Callers hold the lock.
+func (f *FakeEngine) subnetFree(subnet string) bool {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return false
+	}
+	for _, n := range f.networks {
+		for _, config := range n.IPAM.Config {
+			if _, other, err := net.ParseCIDR(config.Subnet); err == nil && (other.Contains(ipNet.IP) || ipNet.Contains(other.IP)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// attach fills the endpoint of a container on a network, allocating its address in the
+// subnet unless settings holds one. Callers hold the lock.
+func (f *FakeEngine) attach(n *network.Inspect, settings *network.EndpointSettings) {
+	settings.NetworkID = n.ID
+	settings.EndpointID = randomID()
+	settings.MacAddress = fmt.Sprintf("02:42:ac:11:%02x:%02x", f.nextIP/256%256, f.nextIP%256)
+	if len(n.IPAM.Config) == 0 {
+		return
+	}
+	_, ipNet, err := net.ParseCIDR(n.IPAM.Config[0].Subnet)
+	if err != nil {
+		return
+	}
+	settings.Gateway = n.IPAM.Config[0].Gateway
+	settings.IPPrefixLen, _ = ipNet.Mask.Size()
+	if settings.IPAMConfig != nil && settings.IPAMConfig.IPv4Address != "" {
+		settings.IPAddress = settings.IPAMConfig.IPv4Address
+		return
+	}
+	ip := ipNet.IP.To4()
+	if ip == nil {
+		return
+	}
+	settings.IPAddress = net.IPv4(ip[0], ip[1], ip[2]+byte(f.nextIP/256), ip[3]+byte(f.nextIP%256)).String()
+	f.nextIP++
+}
+
+// lookupNetwork resolves a network name, ID or unique ID prefix. Callers hold the lock.
+func (f *FakeEngine) lookupNetwork(ref string) (*network.Inspect, error) {
+	if n, ok := f.networks[ref]; ok {
+		return n, nil
+	}
+	for _, n := range f.networks {
+		if n.Name == ref {
+			return n, nil
+		}
+	}
+
+	var found *network.Inspect
+	if ref != "" {
+		for id, n := range f.networks {
+			if strings.HasPrefix(id, ref) {
+				if found != nil {
+					return nil, errdefs.InvalidParameter(fmt.Errorf("network %s is ambiguous", ref))
+				}
+				found = n
+			}
+		}
+	}
+	if found == nil {
+		return nil, errdefs.NotFound(fmt.Errorf("network %s not found", ref))
+	}
+	return found, nil
+}
+
+// networkContainers returns the IDs of the containers connected to a network. Callers hold the lock.
+func (f *FakeEngine) networkContainers(name string) []string {
+	ids := []string{}
+	for _, c := range f.containers {
+		if _, ok := c.NetworkSettings.Networks[name]; ok {
+			ids = append(ids, c.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// removeNetwork deletes a network. Callers hold the lock.
+func (f *FakeEngine) removeNetwork(n *network.Inspect) {
+	delete(f.networks, n.ID)
+	f.publishNetwork(n, events.ActionDestroy)
+}
+
+// matchNetwork tells if a network matches the filters of the daemon. Callers hold the lock.
+func (f *FakeEngine) matchNetwork(args filters.Args, n *network.Inspect) bool {
+	if args.Contains("name") && !args.Match("name", n.Name) {
+		return false
+	}
+	if args.Contains("id") && !args.Match("id", n.ID) {
+		return false
+	}
+	if args.Contains("driver") && !args.ExactMatch("driver", n.Driver) {
+		return false
+	}
+	if args.Contains("scope") && !args.ExactMatch("scope", n.Scope) {
+		return false
+	}
+	if args.Contains("label") && !args.MatchKVList("label", n.Labels) {
+		return false
+	}
+	if args.Contains("type") {
+		kind := "custom"
+		if isPredefinedNetwork(n.Name) {
+			kind = "builtin"
+		}
+		if !args.ExactMatch("type", kind) {
+			return false
+		}
+	}
+	if args.Contains("dangling") {
+		dangling := !isPredefinedNetwork(n.Name) && len(f.networkContainers(n.Name)) == 0
+		if !args.ExactMatch("dangling", fmt.Sprint(dangling)) {
+			return false
+		}
+	}
+	return true
+}
+
+// publishNetwork sends a network event to subscribers. Callers hold the lock.
+func (f *FakeEngine) publishNetwork(n *network.Inspect, action events.Action) {
+	f.broadcast(events.Message{
+		Type:   events.NetworkEventType,
+		Action: action,
+		Actor:  events.Actor{ID: n.ID, Attributes: map[string]string{"name": n.Name, "type": n.Driver}},
+	})
+}
+
+// publishEndpoint sends the connection of a container to a network to subscribers. Callers hold the lock.
+func (f *FakeEngine) publishEndpoint(n *network.Inspect, c *types.ContainerJSON, action events.Action) {
+	f.broadcast(events.Message{
+		Type:   events.NetworkEventType,
+		Action: action,
+		Actor:  events.Actor{ID: n.ID, Attributes: map[string]string{"name": n.Name, "type": n.Driver, "container": c.ID}},
+	})
+}
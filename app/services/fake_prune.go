@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+)
+
+// ContainersPrune removes the containers which are not running and match the until and label filters.
+func (f *FakeEngine) ContainersPrune(_ context.Context, args filters.Args) (container.PruneReport, error) {
+	filter, err := newPruneFilter(args)
+	if err != nil {
+		return container.PruneReport{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := container.PruneReport{ContainersDeleted: []string{}}
+	for _, c := range f.containers {
+		created, _ := time.Parse(time.RFC3339Nano, c.Created)
+		if c.State.Running || !filter.match(created, c.Config.Labels) {
+			continue
+		}
+		report.ContainersDeleted = append(report.ContainersDeleted, c.ID)
+		report.SpaceReclaimed += uint64(f.sizes[c.ID])
+		f.remove(c, false)
+	}
+	sort.Strings(report.ContainersDeleted)
+	return report, nil
+}
+
+// ImagesPrune removes the images without containers matching the until and label filters,
+// only the untagged ones unless the dangling filter is false.
+func (f *FakeEngine) ImagesPrune(_ context.Context, args filters.Args) (image.PruneReport, error) {
+	filter, err := newPruneFilter(args)
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+	danglingOnly := !args.Contains("dangling") || args.ExactMatch("dangling", "true") || args.ExactMatch("dangling", "1")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := image.PruneReport{ImagesDeleted: []image.DeleteResponse{}}
+	for _, img := range f.images {
+		if len(f.imageContainers(img.ID)) > 0 || (danglingOnly && len(img.RepoTags) > 0) || !filter.match(time.Unix(img.Created, 0), img.Labels) {
+			continue
+		}
+		for _, tag := range img.RepoTags {
+			report.ImagesDeleted = append(report.ImagesDeleted, image.DeleteResponse{Untagged: tag})
+		}
+		report.ImagesDeleted = append(report.ImagesDeleted, image.DeleteResponse{Deleted: img.ID})
+		report.SpaceReclaimed += uint64(img.Size)
+		delete(f.images, img.ID)
+		f.publishImage(img.ID, events.ActionDelete, img.ID)
+	}
+	return report, nil
+}
+
+// NetworksPrune removes the custom networks without containers matching the until and label filters.
+func (f *FakeEngine) NetworksPrune(_ context.Context, args filters.Args) (network.PruneReport, error) {
+	filter, err := newPruneFilter(args)
+	if err != nil {
+		return network.PruneReport{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := network.PruneReport{NetworksDeleted: []string{}}
+	for _, n := range f.networks {
+		if isPredefinedNetwork(n.Name) || len(f.networkContainers(n.Name)) > 0 || !filter.match(n.Created, n.Labels) {
+			continue
+		}
+		report.NetworksDeleted = append(report.NetworksDeleted, n.Name)
+		f.removeNetwork(n)
+	}
+	sort.Strings(report.NetworksDeleted)
+	return report, nil
+}
+
+// BuildCachePrune removes the build cache not in use, last used before the until filter.
+// Fake records have no parent, options.All and options.KeepStorage make no difference.
+func (f *FakeEngine) BuildCachePrune(_ context.Context, options types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error) {
+	filter, err := newPruneFilter(options.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := &types.BuildCachePruneReport{CachesDeleted: []string{}}
+	for id, record := range f.buildCache {
+		if record.InUse || !filter.match(lastUsed(record), nil) {
+			continue
+		}
+		report.CachesDeleted = append(report.CachesDeleted, id)
+		report.SpaceReclaimed += uint64(record.Size)
+		delete(f.buildCache, id)
+	}
+	sort.Strings(report.CachesDeleted)
+	return report, nil
+}
+
+// DiskUsage returns the space used by the images, the writable layers of the containers,
+// the volumes and the build cache. Every type is returned whatever options.Types.
+func (f *FakeEngine) DiskUsage(_ context.Context, _ types.DiskUsageOptions) (types.DiskUsage, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	usage := types.DiskUsage{}
+	for _, img := range f.images {
+		summary := *img
+		summary.Containers = int64(len(f.imageContainers(img.ID)))
+		summary.SharedSize = 0
+		usage.LayersSize += img.Size
+		usage.Images = append(usage.Images, &summary)
+	}
+	for _, c := range f.containers {
+		summary := f.summary(c)
+		summary.SizeRw = f.sizes[c.ID]
+		usage.Containers = append(usage.Containers, &summary)
+	}
+	for _, v := range f.volumes {
+		clone := f.cloneVolume(v, true)
+		usage.Volumes = append(usage.Volumes, &clone)
+	}
+	for _, record := range f.buildCache {
+		copied := *record
+		usage.BuildCache = append(usage.BuildCache, &copied)
+	}
+	return usage, nil
+}
+
+// addBuildCache registers the cache of a past build, one record still in use. Callers hold the lock.
+func (f *FakeEngine) addBuildCache() {
+	now := time.Now().UTC()
+	records := []types.BuildCache{
+		{Type: "regular", Description: "[build 3/4] RUN go build -o /app ./cmd/app", Size: 180 * 1024 * 1024, CreatedAt: now.Add(-48 * time.Hour)},
+		{Type: "exec.cachemount", Description: "mount / from exec /bin/sh -c go mod download", Size: 450 * 1024 * 1024, CreatedAt: now.Add(-240 * time.Hour)},
+		{Type: "source.local", Description: "local source for context", Size: 12 * 1024 * 1024, CreatedAt: now.Add(-3 * time.Hour)},
+		{Type: "regular", Description: "[base 1/1] FROM docker.io/library/golang:1.22", Size: 64 * 1024 * 1024, CreatedAt: now.Add(-time.Hour), InUse: true},
+	}
+	for i := range records {
+		record := records[i]
+		record.ID = randomID()[:25]
+		lastUsed := record.CreatedAt.Add(time.Minute)
+		record.LastUsedAt = &lastUsed
+		record.UsageCount = 1
+		f.buildCache[record.ID] = &record
+	}
+}
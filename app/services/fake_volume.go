@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	mathrand "math/rand"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+)
+
+// VolumeList lists the volumes matching the dangling, driver, label and name filters, by name.
+// Like the daemon, the list does not hold the usage of the volumes.
+func (f *FakeEngine) VolumeList(_ context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	response := volume.ListResponse{Volumes: []*volume.Volume{}, Warnings: []string{}}
+	for _, v := range f.volumes {
+		if !f.matchVolume(options.Filters, v) {
+			continue
+		}
+		clone := f.cloneVolume(v, false)
+		response.Volumes = append(response.Volumes, &clone)
+	}
+	sort.Slice(response.Volumes, func(i, j int) bool { return response.Volumes[i].Name < response.Volumes[j].Name })
+	return response, nil
+}
+
+// VolumeInspect returns a volume given its name, without its usage.
+func (f *FakeEngine) VolumeInspect(_ context.Context, name string) (volume.Volume, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	v, ok := f.volumes[name]
+	if !ok {
+		return volume.Volume{}, errdefs.NotFound(fmt.Errorf("get %s: no such volume", name))
+	}
+	return f.cloneVolume(v, false), nil
+}
+
+// VolumeCreate registers an empty volume of the local driver, named randomly when options.Name
+// is empty. Like the daemon, creating an existing volume of the same driver returns it.
+func (f *FakeEngine) VolumeCreate(_ context.Context, options volume.CreateOptions) (volume.Volume, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	driver := options.Driver
+	if driver == "" {
+		driver = "local"
+	}
+	if driver != "local" {
+		return volume.Volume{}, errdefs.NotFound(fmt.Errorf("error looking up volume plugin %s: plugin %q not found", driver, driver))
+	}
+	name := options.Name
+	if name == "" {
+		name = randomID()
+	}
+	if v, ok := f.volumes[name]; ok {
+		return f.cloneVolume(v, false), nil
+	}
+
+	v := f.addVolume(name, options.Labels)
+	v.UsageData.Size = 0
+	for key, value := range options.DriverOpts {
+		v.Options[key] = value
+	}
+	return f.cloneVolume(v, false), nil
+}
+
+// VolumeRemove deletes a volume unused by containers; force ignores a missing volume.
+func (f *FakeEngine) VolumeRemove(_ context.Context, name string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	v, ok := f.volumes[name]
+	if !ok {
+		if force {
+			return nil
+		}
+		return errdefs.NotFound(fmt.Errorf("get %s: no such volume", name))
+	}
+	if users := f.volumeContainers(name); len(users) > 0 {
+		return errdefs.Conflict(fmt.Errorf("remove %s: volume is in use - %v", name, users))
+	}
+	f.removeVolume(v)
+	return nil
+}
+
+// addVolumes creates the named volumes mounted by a container, and an anonymous
+// volume for each of the volumes of its configuration. Callers hold the lock.
+func (f *FakeEngine) addVolumes(c *types.ContainerJSON) {
+	mounted := make(map[string]bool)
+	for _, m := range c.Mounts {
+		mounted[m.Destination] = true
+		if m.Type == mount.TypeVolume {
+			if _, ok := f.volumes[m.Name]; !ok {
+				f.addVolume(m.Name, nil)
+			}
+		}
+	}
+
+	destinations := make([]string, 0, len(c.Config.Volumes))
+	for destination := range c.Config.Volumes {
+		if !mounted[destination] {
+			destinations = append(destinations, destination)
+		}
+	}
+	sort.Strings(destinations)
+	for _, destination := range destinations {
+		v := f.addVolume(randomID(), map[string]string{anonymousVolumeLabel: ""})
+		c.Mounts = append(c.Mounts, types.MountPoint{
+			Type:        mount.TypeVolume,
+			Name:        v.Name,
+			Source:      v.Mountpoint,
+			Destination: destination,
+			Driver:      v.Driver,
+			RW:          true,
+		})
+	}
+}
+
+// addVolume registers an empty local volume of a random size. Callers hold the lock.
+func (f *FakeEngine) addVolume(name string, labels map[string]string) *volume.Volume {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	v := &volume.Volume{
+		Name:       name,
+		Driver:     "local",
+		Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Labels:     labels,
+		Options:    map[string]string{},
+		Scope:      "local",
+		UsageData:  &volume.UsageData{Size: mathrand.Int63n(256 * 1024 * 1024), RefCount: 0},
+	}
+	f.volumes[name] = v
+	f.publishVolume(v, events.ActionCreate)
+	return v
+}
+
+// volumeContainers returns the IDs of the containers mounting a volume. Callers hold the lock.
+func (f *FakeEngine) volumeContainers(name string) []string {
+	ids := []string{}
+	for _, c := range f.containers {
+		for _, m := range c.Mounts {
+			if m.Type == mount.TypeVolume && m.Name == name {
+				ids = append(ids, c.ID)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// cloneVolume copies a volume with its usage, which the daemon only computes for the disk usage.
+func (f *FakeEngine) cloneVolume(v *volume.Volume, usage bool) volume.Volume {
+	clone := *v
+	clone.UsageData = nil
+	if usage {
+		clone.UsageData = &volume.UsageData{Size: v.UsageData.Size, RefCount: int64(len(f.volumeContainers(v.Name)))}
+	}
+	return clone
+}
+
+// matchVolume tells if a volume matches the filters of the daemon. Callers hold the lock.
+func (f *FakeEngine) matchVolume(args filters.Args, v *volume.Volume) bool {
+	if args.Contains("name") && !args.Match("name", v.Name) {
+		return false
+	}
+	if args.Contains("driver") && !args.ExactMatch("driver", v.Driver) {
+		return false
+	}
+	if args.Contains("label") && !args.MatchKVList("label", v.Labels) {
+		return false
+	}
+	if args.Contains("dangling") {
+		dangling := len(f.volumeContainers(v.Name)) == 0
+		if !args.ExactMatch("dangling", fmt.Sprint(dangling)) {
+			return false
+		}
+	}
+	return true
+}
+
+// removeVolume deletes a volume. Callers hold the lock.
+func (f *FakeEngine) removeVolume(v *volume.Volume) {
+	delete(f.volumes, v.Name)
+	f.publishVolume(v, events.ActionDestroy)
+}
+
+// publishVolume sends a volume event to subscribers. Callers hold the lock.
+func (f *FakeEngine) publishVolume(v *volume.Volume, action events.Action) {
+	f.broadcast(events.Message{
+		Type:   events.VolumeEventType,
+		Action: action,
+		Actor:  events.Actor{ID: v.Name, Attributes: map[string]string{"driver": v.Driver}},
+	})
+}
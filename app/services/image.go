@@ -0,0 +1,209 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+)
+
+// imageFilterKeys maps the filter keys of the list endpoint to the daemon filter
+// they are pushed down to. Empty when the daemon has no equivalent.
+var imageFilterKeys = map[string]string{
+	"id":        "",
+	"reference": "reference",
+	"label":     "label",
+	"dangling":  "dangling",
+}
+
+type Image struct {
+	clientDocker DockerBackend
+	validate     *validator.Validate
+	logs         *zerolog.Logger
+}
+
+func NewServiceImage(backend DockerBackend, logs *zerolog.Logger) *Image {
+	return &Image{
+		clientDocker: backend,
+		validate:     validator.New(),
+		logs:         logs,
+	}
+}
+
+// List returns the images matching the filters, filter_like and search clauses of params,
+// sorted by its sort clause. Intermediate images are only listed with params.All.
+func (i *Image) List(ctx context.Context, params *models.QueryParams) ([]image.Summary, error) {
+	exact, err := parseClauses(params.FilterClause, imageFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	like, err := parseClauses(params.FilterLikeClause, imageFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	if err := boolClauses(exact, "dangling"); err != nil {
+		return nil, err
+	}
+	if err := boolClauses(like, "dangling"); err != nil {
+		return nil, err
+	}
+
+	options := image.ListOptions{All: params.All, ContainerCount: true, Filters: filters.NewArgs()}
+	for key, values := range exact {
+		dockerKey := imageFilterKeys[key]
+		// The daemon refuses several dangling values, they are checked below
+		if dockerKey == "" || (key == "dangling" && len(values) > 1) {
+			continue
+		}
+		for _, value := range values {
+			options.Filters.Add(dockerKey, value)
+		}
+	}
+
+	images, err := i.clientDocker.ImageList(ctx, options)
+	if err != nil {
+		return nil, logError(i.logs, err)
+	}
+
+	filtered := images[:0]
+	for k := range images {
+		img := &images[k]
+		fields := func(key string) []string { return imageFields(img, key) }
+		if matchClauses(exact, false, fields, matchImageField) && matchClauses(like, true, fields, matchImageField) && matchImageSearch(img, params.SearchClause) {
+			filtered = append(filtered, *img)
+		}
+	}
+	if err := sortItems(filtered, params.SortClause); err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// Inspect returns the low-level information of an image given its reference, ID or a unique ID prefix.
+func (i *Image) Inspect(ctx context.Context, ref string) (types.ImageInspect, error) {
+	inspect, _, err := i.clientDocker.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return types.ImageInspect{}, logError(i.logs, err)
+	}
+	return inspect, nil
+}
+
+// Pull pulls the image of spec and returns it. progress is called for every pull message.
+func (i *Image) Pull(ctx context.Context, spec *models.ImagePull, progress func(models.PullProgress) error) (types.ImageInspect, error) {
+	if err := i.validate.Struct(spec); err != nil {
+		return types.ImageInspect{}, errdefs.InvalidParameter(err)
+	}
+	ref, err := normalizeImage(spec.Image)
+	if err != nil {
+		return types.ImageInspect{}, err
+	}
+	i.logs.Info().Str("image", ref).Msg("Pulling image")
+	if err := pullImage(ctx, i.clientDocker, ref, progress); err != nil {
+		return types.ImageInspect{}, logError(i.logs, err)
+	}
+	return i.Inspect(ctx, ref)
+}
+
+// Tag adds the tag of spec to an image.
+func (i *Image) Tag(ctx context.Context, ref string, spec *models.ImageTag) (models.ImageTagged, error) {
+	if err := i.validate.Struct(spec); err != nil {
+		return models.ImageTagged{}, errdefs.InvalidParameter(err)
+	}
+	tag, err := normalizeImage(spec.Tag)
+	if err != nil {
+		return models.ImageTagged{}, err
+	}
+	if strings.Contains(tag, "@") {
+		return models.ImageTagged{}, errdefs.InvalidParameter(fmt.Errorf("invalid tag %q, a digest cannot be a tag", spec.Tag))
+	}
+	inspect, err := i.Inspect(ctx, ref)
+	if err != nil {
+		return models.ImageTagged{}, err
+	}
+	tagged := models.ImageTagged{ID: inspect.ID, Tag: tag}
+	return tagged, logError(i.logs, i.clientDocker.ImageTag(ctx, inspect.ID, tag))
+}
+
+// Remove untags an image, and deletes it with its untagged parents once it has no more
+// tags. force removes an image with several tags or used by stopped containers, noprune
+// keeps the untagged parents.
+func (i *Image) Remove(ctx context.Context, ref string, force, noprune bool) ([]image.DeleteResponse, error) {
+	deleted, err := i.clientDocker.ImageRemove(ctx, ref, image.RemoveOptions{Force: force, PruneChildren: !noprune})
+	if err != nil {
+		return nil, logError(i.logs, err)
+	}
+	return deleted, nil
+}
+
+// History returns the layers of an image, newest first.
+func (i *Image) History(ctx context.Context, ref string) ([]image.HistoryResponseItem, error) {
+	history, err := i.clientDocker.ImageHistory(ctx, ref)
+	if err != nil {
+		return nil, logError(i.logs, err)
+	}
+	return history, nil
+}
+
+func matchImageField(key, field, value string) bool {
+	switch key {
+	case "id":
+		return strings.HasPrefix(strings.TrimPrefix(field, "sha256:"), strings.TrimPrefix(value, "sha256:"))
+	case "reference":
+		// Patterns like the daemon, a reference without tag matches every tag of the repository
+		if normalized, err := normalizeImage(value); err == nil && field == strings.ToLower(normalized) {
+			return true
+		}
+		repository, _, _ := strings.Cut(field, "@")
+		if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+			repository = repository[:i]
+		}
+		for _, candidate := range []string{field, repository} {
+			if ok, _ := path.Match(value, candidate); ok {
+				return true
+			}
+		}
+		return false
+	case "label":
+		return matchLabel(field, value)
+	default:
+		return field == value
+	}
+}
+
+// imageFields returns the values of an image a filter key is checked against.
+func imageFields(img *image.Summary, key string) []string {
+	switch key {
+	case "id":
+		return []string{img.ID}
+	case "reference":
+		return append(append([]string{}, img.RepoTags...), img.RepoDigests...)
+	case "label":
+		return labelFields(img.Labels)
+	case "dangling":
+		return []string{strconv.FormatBool(isDanglingImage(img.RepoTags))}
+	}
+	return nil
+}
+
+// isDanglingImage tells if an image has no tags, older daemons list "<none>:<none>".
+func isDanglingImage(tags []string) bool {
+	return len(tags) == 0 || (len(tags) == 1 && tags[0] == "<none>:<none>")
+}
+
+// matchImageSearch tells if every keyword appears in the ID, a tag, a digest or a label of an image.
+func matchImageSearch(img *image.Summary, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	fields := append([]string{img.ID}, imageFields(img, "reference")...)
+	return matchSearch(keywords, append(fields, imageFields(img, "label")...)...)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestImages(t *testing.T) {
+	logs := zerolog.Nop()
+	engine := NewFakeEngine()
+	images := NewServiceImage(engine, &logs)
+	ctx := context.Background()
+
+	tags := func(params *models.QueryParams) []string {
+		t.Helper()
+		list, err := images.List(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tags := []string{}
+		for _, img := range list {
+			tags = append(tags, img.RepoTags...)
+		}
+		return tags
+	}
+
+	pulled, err := images.Pull(ctx, &models.ImagePull{Image: "alpine:3.20"}, nil)
+	if err != nil || len(pulled.RepoTags) != 1 || pulled.RepoTags[0] != "alpine:3.20" {
+		t.Fatalf("pulled image expected, got %v %v", pulled.RepoTags, err)
+	}
+	if _, err := images.Pull(ctx, &models.ImagePull{}, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("an image is required, got %v", err)
+	}
+
+	if got := tags(&models.QueryParams{SortClause: []string{"-Size"}}); len(got) != 3 || got[0] != "nginx:latest" {
+		t.Errorf("images sorted by decreasing size expected, got %v", got)
+	}
+	if got := tags(&models.QueryParams{FilterClause: []string{"reference:alpine"}}); len(got) != 1 || got[0] != "alpine:3.20" {
+		t.Errorf("a reference without tag should match every tag, got %v", got)
+	}
+	if got := tags(&models.QueryParams{FilterClause: []string{"reference:redis", "reference:ngi*"}}); len(got) != 2 {
+		t.Errorf("references should match any value and patterns, got %v", got)
+	}
+	if got := tags(&models.QueryParams{FilterLikeClause: []string{"reference:PIN"}, SearchClause: []string{"alp"}}); len(got) != 1 {
+		t.Errorf("filter_like and search should match substrings, got %v", got)
+	}
+	if _, err := images.List(ctx, &models.QueryParams{FilterClause: []string{"dangling:maybe"}}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("dangling should be a boolean, got %v", err)
+	}
+
+	tagged, err := images.Tag(ctx, "alpine:3.20", &models.ImageTag{Tag: "registry.local/base"})
+	if err != nil || tagged.ID != pulled.ID || tagged.Tag != "registry.local/base:latest" {
+		t.Fatalf("tag expected on the pulled image, got %+v %v", tagged, err)
+	}
+	if _, err := images.Tag(ctx, "missing", &models.ImageTag{Tag: "other"}); !errdefs.IsNotFound(err) {
+		t.Errorf("tagging a missing image should be not found, got %v", err)
+	}
+	if _, err := images.Remove(ctx, pulled.ID[7:19], false, false); !errdefs.IsConflict(err) {
+		t.Errorf("an image with several tags should need force, got %v", err)
+	}
+	deleted, err := images.Remove(ctx, "registry.local/base", false, false)
+	if err != nil || len(deleted) != 1 || deleted[0].Untagged != "registry.local/base:latest" {
+		t.Errorf("removing a tag should only untag, got %+v %v", deleted, err)
+	}
+	deleted, err = images.Remove(ctx, "alpine:3.20", false, false)
+	if err != nil || deleted[len(deleted)-1].Deleted != pulled.ID {
+		t.Errorf("removing the last tag should delete the image, got %+v %v", deleted, err)
+	}
+	if _, err := images.Inspect(ctx, "alpine:3.20"); !errdefs.IsNotFound(err) {
+		t.Errorf("deleted image should be not found, got %v", err)
+	}
+
+	// nginx runs fake-nginx, redis is used by the stopped fake-redis
+	if _, err := images.Remove(ctx, "nginx", true, false); !errdefs.IsConflict(err) {
+		t.Errorf("an image of a running container cannot be forced, got %v", err)
+	}
+	if _, err := images.Remove(ctx, "redis", false, false); !errdefs.IsConflict(err) {
+		t.Errorf("an image of a stopped container should need force, got %v", err)
+	}
+	if _, err := images.Remove(ctx, "redis", true, false); err != nil {
+		t.Errorf("forced removal should succeed, got %v", err)
+	}
+
+	history, err := images.History(ctx, "nginx:latest")
+	if err != nil || len(history) == 0 || history[0].Tags[0] != "nginx:latest" {
+		t.Errorf("history of the image expected, got %+v %v", history, err)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Logs reads the output of a container and calls send for every line.
+// With options.Follow it returns when ctx is done or the container stops.
+func (c *Container) Logs(ctx context.Context, ref string, options container.LogsOptions, send func(models.LogLine) error) error {
+	if !options.ShowStdout && !options.ShowStderr {
+		return errdefs.InvalidParameter(errors.New("at least one of stdout or stderr must be selected"))
+	}
+	for _, value := range []string{options.Since, options.Until} {
+		if _, err := logTime(value); err != nil {
+			return err
+		}
+	}
+
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	reader, err := c.clientDocker.ContainerLogs(ctx, inspect.ID, options)
+	if err != nil {
+		return logError(c.logs, err)
+	}
+	defer reader.Close()
+
+	stdout := &lineWriter{stream: "stdout", timestamps: options.Timestamps, send: send}
+	stderr := &lineWriter{stream: "stderr", timestamps: options.Timestamps, send: send}
+
+	// TTY containers are not multiplexed
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	}
+	if err == nil {
+		err = stdout.flush()
+	}
+	if err == nil {
+		err = stderr.flush()
+	}
+	if err != nil && ctx.Err() != nil {
+		// Client went away
+		return nil
+	}
+	return err
+}
+
+// lineWriter splits a demultiplexed stream into log lines.
+type lineWriter struct {
+	stream     string
+	timestamps bool
+	send       func(models.LogLine) error
+	buf        []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.buf[:i])
+		w.buf = w.buf[i+1:]
+		if err := w.emit(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// flush sends the last line when the stream does not end with a new line.
+func (w *lineWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := string(w.buf)
+	w.buf = nil
+	return w.emit(line)
+}
+
+func (w *lineWriter) emit(line string) error {
+	logLine := models.LogLine{Stream: w.stream}
+	line = strings.TrimSuffix(line, "\r")
+	if w.timestamps {
+		if i := strings.IndexByte(line, ' '); i > 0 {
+			logLine.Timestamp, line = line[:i], line[i+1:]
+		}
+	}
+	logLine.Message = line
+	return w.send(logLine)
+}
+
+// logTime parses a since/until value the way the daemon does, a timestamp, an RFC 3339
+// date or a duration before now. The zero time stands for an empty value.
+func logTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	ts, err := timetypes.GetTimestamp(value, time.Now())
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	return time.Unix(sec, nsec), nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+)
+
+// networkFilterKeys maps the filter keys of the list endpoint to the daemon filter
+// they are pushed down to.
+var networkFilterKeys = map[string]string{
+	"id":     "id",
+	"name":   "name",
+	"driver": "driver",
+	"label":  "label",
+	"scope":  "scope",
+	"type":   "type",
+}
+
+type Network struct {
+	clientDocker DockerBackend
+	validate     *validator.Validate
+	logs         *zerolog.Logger
+}
+
+func NewServiceNetwork(backend DockerBackend, logs *zerolog.Logger) *Network {
+	return &Network{
+		clientDocker: backend,
+		validate:     validator.New(),
+		logs:         logs,
+	}
+}
+
+// List returns the networks of scope matching the filters, filter_like and search clauses
+// of params, sorted by its sort clause.
+func (n *Network) List(ctx context.Context, params *models.QueryParams, scope models.Scope) ([]network.Summary, error) {
+	exact, err := parseClauses(params.FilterClause, networkFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	like, err := parseClauses(params.FilterLikeClause, networkFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Push the exact filters down to the daemon, they are checked again below
+	// as the daemon is more lenient (name matches a part of the name).
+	options := network.ListOptions{Filters: filters.NewArgs()}
+	for key, values := range exact {
+		for _, value := range values {
+			options.Filters.Add(networkFilterKeys[key], value)
+		}
+	}
+	for key, value := range scope {
+		options.Filters.Add("label", key+"="+value)
+	}
+
+	networks, err := n.clientDocker.NetworkList(ctx, options)
+	if err != nil {
+		return nil, logError(n.logs, err)
+	}
+
+	filtered := networks[:0]
+	for i := range networks {
+		nw := &networks[i]
+		fields := func(key string) []string { return networkFields(nw, key) }
+		if scope.Matches(nw.Labels) && matchClauses(exact, false, fields, matchNetworkField) && matchClauses(like, true, fields, matchNetworkField) && matchNetworkSearch(nw, params.SearchClause) {
+			filtered = append(filtered, *nw)
+		}
+	}
+	if err := sortItems(filtered, params.SortClause); err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// Inspect returns a network of scope given its name, ID or a unique ID prefix, with the
+// containers of scope attached to it. Networks out of the scope are reported as not found.
+func (n *Network) Inspect(ctx context.Context, ref string, scope models.Scope) (network.Inspect, error) {
+	inspect, err := n.clientDocker.NetworkInspect(ctx, ref, network.InspectOptions{})
+	if err != nil {
+		return network.Inspect{}, logError(n.logs, err)
+	}
+	if !scope.Matches(inspect.Labels) {
+		return network.Inspect{}, errdefs.NotFound(fmt.Errorf("network %s not found", ref))
+	}
+	if len(scope) == 0 || len(inspect.Containers) == 0 {
+		return inspect, nil
+	}
+
+	options := container.ListOptions{All: true, Filters: filters.NewArgs()}
+	for key, value := range scope {
+		options.Filters.Add("label", key+"="+value)
+	}
+	containers, err := n.clientDocker.ContainerList(ctx, options)
+	if err != nil {
+		return network.Inspect{}, logError(n.logs, err)
+	}
+	inScope := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		inScope[c.ID] = scope.Matches(c.Labels)
+	}
+	for id := range inspect.Containers {
+		if !inScope[id] {
+			delete(inspect.Containers, id)
+		}
+	}
+	return inspect, nil
+}
+
+// Create creates the network of spec.
+func (n *Network) Create(ctx context.Context, spec *models.NetworkSpec) (models.NetworkCreated, error) {
+	if err := n.validate.Struct(spec); err != nil {
+		return models.NetworkCreated{}, errdefs.InvalidParameter(err)
+	}
+
+	options := network.CreateOptions{Driver: spec.Driver, Internal: spec.Internal, Labels: spec.Labels}
+	if spec.Subnet != "" {
+		_, subnet, _ := net.ParseCIDR(spec.Subnet)
+		if spec.Gateway != "" && !subnet.Contains(net.ParseIP(spec.Gateway)) {
+			return models.NetworkCreated{}, errdefs.InvalidParameter(fmt.Errorf("gateway %s is out of the subnet %s", spec.Gateway, spec.Subnet))
+		}
+		options.IPAM = &network.IPAM{
+			Driver: "default",
+			Config: []network.IPAMConfig{{Subnet: spec.Subnet, Gateway: spec.Gateway}},
+		}
+	} else if spec.Gateway != "" {
+		return models.NetworkCreated{}, errdefs.InvalidParameter(errors.New("a gateway requires a subnet"))
+	}
+
+	response, err := n.clientDocker.NetworkCreate(ctx, spec.Name, options)
+	if err != nil {
+		return models.NetworkCreated{}, logError(n.logs, err)
+	}
+	return models.NetworkCreated{ID: response.ID, Name: spec.Name, Warning: response.Warning}, nil
+}
+
+// Remove removes a network of scope without containers.
+func (n *Network) Remove(ctx context.Context, ref string, scope models.Scope) error {
+	inspect, err := n.Inspect(ctx, ref, scope)
+	if err != nil {
+		return err
+	}
+	return logError(n.logs, n.clientDocker.NetworkRemove(ctx, inspect.ID))
+}
+
+// Connect attaches a container of scope to a network of scope.
+func (n *Network) Connect(ctx context.Context, ref string, spec *models.NetworkConnect, scope models.Scope) error {
+	if err := n.validate.Struct(spec); err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	networkID, containerID, err := n.endpoint(ctx, ref, spec.Container, scope)
+	if err != nil {
+		return err
+	}
+
+	settings := &network.EndpointSettings{Aliases: spec.Aliases}
+	if spec.IPAddress != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: spec.IPAddress}
+	}
+	return logError(n.logs, n.clientDocker.NetworkConnect(ctx, networkID, containerID, settings))
+}
+
+// Disconnect detaches a container of scope from a network of scope.
+func (n *Network) Disconnect(ctx context.Context, ref string, spec *models.NetworkDisconnect, scope models.Scope) error {
+	if err := n.validate.Struct(spec); err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	networkID, containerID, err := n.endpoint(ctx, ref, spec.Container, scope)
+	if err != nil {
+		return err
+	}
+	return logError(n.logs, n.clientDocker.NetworkDisconnect(ctx, networkID, containerID, spec.Force))
+}
+
+// endpoint resolves the IDs of a network and a container of scope.
+func (n *Network) endpoint(ctx context.Context, ref, containerRef string, scope models.Scope) (string, string, error) {
+	inspect, err := n.Inspect(ctx, ref, scope)
+	if err != nil {
+		return "", "", err
+	}
+	c, err := n.clientDocker.ContainerInspect(ctx, containerRef)
+	if err != nil {
+		return "", "", logError(n.logs, err)
+	}
+	if c.Config == nil || !scope.Matches(c.Config.Labels) {
+		return "", "", errdefs.NotFound(fmt.Errorf("no such container: %s", containerRef))
+	}
+	return inspect.ID, c.ID, nil
+}
+
+func matchNetworkField(key, field, value string) bool {
+	switch key {
+	case "id":
+		return strings.HasPrefix(field, value)
+	case "label":
+		return matchLabel(field, value)
+	default:
+		return field == value
+	}
+}
+
+// networkFields returns the values of a network a filter key is checked against.
+func networkFields(nw *network.Summary, key string) []string {
+	switch key {
+	case "id":
+		return []string{nw.ID}
+	case "name":
+		return []string{nw.Name}
+	case "driver":
+		return []string{nw.Driver}
+	case "label":
+		return labelFields(nw.Labels)
+	case "scope":
+		return []string{nw.Scope}
+	case "type":
+		if isPredefinedNetwork(nw.Name) {
+			return []string{"builtin"}
+		}
+		return []string{"custom"}
+	}
+	return nil
+}
+
+// matchNetworkSearch tells if every keyword appears in the ID, the name, the driver or a label of a network.
+func matchNetworkSearch(nw *network.Summary, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	return matchSearch(keywords, append([]string{nw.ID, nw.Name, nw.Driver}, networkFields(nw, "label")...)...)
+}
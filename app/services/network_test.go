@@ -0,0 +1,98 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestNetworks(t *testing.T) {
+	logs := zerolog.Nop()
+	engine := NewFakeEngine()
+	networks := NewServiceNetwork(engine, &logs)
+	containers := NewServiceContainer(engine, &logs)
+	ctx := context.Background()
+	team := models.Scope{"team": "a"}
+
+	if _, err := networks.Create(ctx, &models.NetworkSpec{Name: "front", Gateway: "10.1.0.1"}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("a gateway without a subnet should be refused, got %v", err)
+	}
+	if _, err := networks.Create(ctx, &models.NetworkSpec{Name: "front", Subnet: "10.1.0.0/24", Gateway: "10.2.0.1"}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("a gateway out of the subnet should be refused, got %v", err)
+	}
+	created, err := networks.Create(ctx, &models.NetworkSpec{Name: "front", Subnet: "10.1.0.0/24", Gateway: "10.1.0.1", Internal: true, Labels: map[string]string{"team": "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := networks.Create(ctx, &models.NetworkSpec{Name: "front"}); !errdefs.IsConflict(err) {
+		t.Errorf("a duplicate name should conflict, got %v", err)
+	}
+
+	listed, err := networks.List(ctx, &models.QueryParams{FilterClause: []string{"type:custom"}}, nil)
+	if err != nil || len(listed) != 1 || listed[0].ID != created.ID {
+		t.Errorf("the custom network expected, got %+v %v", listed, err)
+	}
+	if scoped, _ := networks.List(ctx, &models.QueryParams{}, team); len(scoped) != 1 {
+		t.Errorf("a scoped caller should only list its networks, got %+v", scoped)
+	}
+	if _, err := networks.Inspect(ctx, "bridge", team); !errdefs.IsNotFound(err) {
+		t.Errorf("a network out of the scope should not be found, got %v", err)
+	}
+
+	api, err := engine.ContainerCreate(ctx, &container.Config{Image: "nginx:latest", Labels: map[string]string{"team": "a"}}, nil, nil, nil, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := networks.Connect(ctx, "front", &models.NetworkConnect{Container: "fake-nginx"}, team); !errdefs.IsNotFound(err) {
+		t.Errorf("a container out of the scope should not be found, got %v", err)
+	}
+	if err := networks.Connect(ctx, "front", &models.NetworkConnect{Container: "api", Aliases: []string{"bad alias"}}, team); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("an alias should be a host name, got %v", err)
+	}
+	if err := networks.Connect(ctx, "front", &models.NetworkConnect{Container: "api", Aliases: []string{"api.front"}, IPAddress: "10.1.0.10"}, team); err != nil {
+		t.Fatal(err)
+	}
+	if err := networks.Connect(ctx, "front", &models.NetworkConnect{Container: "fake-nginx"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	inspect, _ := networks.Inspect(ctx, "front", team)
+	if _, ok := inspect.Containers[api.ID]; !ok || len(inspect.Containers) != 1 {
+		t.Errorf("only the containers of the scope should be listed, got %+v", inspect.Containers)
+	}
+	if err := networks.Remove(ctx, "front", nil); !errdefs.IsForbidden(err) {
+		t.Errorf("a network with containers should not be removed, got %v", err)
+	}
+
+	detail, err := containers.Detail(ctx, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.Networks) != 2 || detail.Networks[0].Name != "bridge" || detail.Networks[1].Name != "front" {
+		t.Fatalf("the container should be on bridge and front, got %+v", detail.Networks)
+	}
+	if front := detail.Networks[1]; front.IPAddress != "10.1.0.10" || front.IPPrefixLen != 24 || front.Gateway != "10.1.0.1" ||
+		!front.Internal || len(front.Aliases) != 1 || front.Aliases[0] != "api.front" {
+		t.Errorf("the endpoint on front should hold the static address and alias, got %+v", front)
+	}
+
+	if err := networks.Disconnect(ctx, "front", &models.NetworkDisconnect{Container: "api"}, team); err != nil {
+		t.Fatal(err)
+	}
+	if err := networks.Disconnect(ctx, "front", &models.NetworkDisconnect{Container: "api"}, team); !errdefs.IsConflict(err) {
+		t.Errorf("a container not connected should conflict, got %v", err)
+	}
+	if err := networks.Remove(ctx, "front", team); !errdefs.IsForbidden(err) {
+		t.Errorf("fake-nginx out of the scope still uses the network, got %v", err)
+	}
+	_ = networks.Disconnect(ctx, "front", &models.NetworkDisconnect{Container: "fake-nginx"}, nil)
+	if err := networks.Remove(ctx, "front", team); err != nil {
+		t.Errorf("an unused network should be removed, got %v", err)
+	}
+	if err := networks.Remove(ctx, "bridge", nil); !errdefs.IsForbidden(err) {
+		t.Errorf("predefined networks should not be removed, got %v", err)
+	}
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+// projectWorkers is the number of containers of a project acted on at the same time.
+const projectWorkers = 8
+
+// ProjectActions are the actions run on all the containers of a project.
+var ProjectActions = []string{"start", "stop", "restart", "down"}
+
+// projectFilterKeys are the filter keys of the list endpoint, checked on the projects.
+var projectFilterKeys = map[string]string{
+	"name":    "",
+	"status":  "",
+	"service": "",
+}
+
+type Project struct {
+	containers   *Container
+	clientDocker DockerBackend
+	logs         *zerolog.Logger
+}
+
+func NewServiceProject(backend DockerBackend, logs *zerolog.Logger) *Project {
+	return &Project{
+		containers:   NewServiceContainer(backend, logs),
+		clientDocker: backend,
+		logs:         logs,
+	}
+}
+
+// List returns the compose projects of the containers of scope matching the filters,
+// filter_like and search clauses of params, sorted by its sort clause or by name.
+func (p *Project) List(ctx context.Context, params *models.QueryParams, scope models.Scope) ([]models.Project, error) {
+	exact, err := parseClauses(params.FilterClause, projectFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	like, err := parseClauses(params.FilterLikeClause, projectFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := p.projectContainers(ctx, "", scope)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := []models.Project{}
+	for _, project := range groupProjects(containers) {
+		fields := func(key string) []string { return projectFields(&project, key) }
+		if matchClauses(exact, false, fields, matchEqual) && matchClauses(like, true, fields, matchEqual) && matchProjectSearch(&project, params.SearchClause) {
+			projects = append(projects, project)
+		}
+	}
+	if err := sortItems(projects, params.SortClause); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// Get returns a compose project of scope given its name.
+func (p *Project) Get(ctx context.Context, name string, scope models.Scope) (models.Project, error) {
+	containers, err := p.projectContainers(ctx, name, scope)
+	if err != nil {
+		return models.Project{}, err
+	}
+	if len(containers) == 0 {
+		return models.Project{}, errdefs.NotFound(fmt.Errorf("no such project: %s", name))
+	}
+	return groupProjects(containers)[0], nil
+}
+
+// Action runs start, stop, restart or down on all the containers of scope of a project at
+// the same time. A container failing does not stop the others, the result tells the outcome
+// for each of them. timeout is given to stop, restart and down, down removing the containers
+// then the networks of the project no container uses anymore.
+func (p *Project) Action(ctx context.Context, name, action string, timeout *int, scope models.Scope) (models.ProjectActionResult, error) {
+	if !functions.Contains(ProjectActions, action) {
+		return models.ProjectActionResult{}, errdefs.InvalidParameter(fmt.Errorf("unknown action %q, expected one of %s", action, strings.Join(ProjectActions, ", ")))
+	}
+	containers, err := p.projectContainers(ctx, name, scope)
+	if err != nil {
+		return models.ProjectActionResult{}, err
+	}
+	if len(containers) == 0 {
+		return models.ProjectActionResult{}, errdefs.NotFound(fmt.Errorf("no such project: %s", name))
+	}
+	p.logs.Info().Str("project", name).Str("action", action).Int("containers", len(containers)).Msg("Running project action")
+
+	results := make([]models.ContainerActionResult, len(containers))
+	sem := make(chan struct{}, projectWorkers)
+	var wg sync.WaitGroup
+	for i := range containers {
+		wg.Add(1)
+		go func(i int, ct *types.Container) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = models.ContainerActionResult{
+				ID:      ct.ID,
+				Name:    containerName(ct),
+				Service: ct.Labels[ComposeServiceLabel],
+				Result:  "done",
+			}
+			if err := p.run(ctx, ct.ID, action, timeout); errors.Is(err, ErrContainerState) {
+				results[i].Result = "skipped"
+			} else if err != nil {
+				results[i].Result, results[i].Error = "failed", err.Error()
+			}
+		}(i, &containers[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Service != results[j].Service {
+			return results[i].Service < results[j].Service
+		}
+		return results[i].Name < results[j].Name
+	})
+	result := models.ProjectActionResult{Project: name, Action: action, Containers: results}
+	for _, r := range results {
+		if r.Result == "failed" {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+	if action == "down" {
+		result.Networks = p.removeNetworks(ctx, name, scope)
+	}
+	return result, nil
+}
+
+// run runs an action on a container of a project.
+func (p *Project) run(ctx context.Context, id, action string, timeout *int) error {
+	switch action {
+	case "start":
+		return p.containers.Start(ctx, id)
+	case "stop":
+		return p.containers.Stop(ctx, id, timeout, "")
+	case "restart":
+		return p.containers.Restart(ctx, id, timeout, "")
+	default:
+		if err := p.containers.Stop(ctx, id, timeout, ""); err != nil && !errors.Is(err, ErrContainerState) {
+			return err
+		}
+		return logError(p.logs, p.clientDocker.ContainerRemove(ctx, id, container.RemoveOptions{}))
+	}
+}
+
+// removeNetworks removes the networks of scope of a project no container uses anymore,
+// and returns their names. Networks still used are kept, as docker compose does.
+func (p *Project) removeNetworks(ctx context.Context, name string, scope models.Scope) []string {
+	options := network.ListOptions{Filters: filters.NewArgs(filters.Arg("label", ComposeProjectLabel+"="+name))}
+	networks, err := p.clientDocker.NetworkList(ctx, options)
+	if err != nil {
+		p.logs.Error().Err(err).Msg("")
+		return []string{}
+	}
+
+	removed := []string{}
+	for _, n := range networks {
+		if !scope.Matches(n.Labels) || isPredefinedNetwork(n.Name) {
+			continue
+		}
+		if err := p.clientDocker.NetworkRemove(ctx, n.ID); err != nil {
+			p.logs.Warn().Err(err).Str("network", n.Name).Msg("Project network kept")
+			continue
+		}
+		removed = append(removed, n.Name)
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// projectContainers returns the containers of scope of a compose project, or of all of them without name.
+func (p *Project) projectContainers(ctx context.Context, name string, scope models.Scope) ([]types.Container, error) {
+	label := ComposeProjectLabel
+	if name != "" {
+		label += "=" + name
+	}
+	options := container.ListOptions{All: true, Filters: filters.NewArgs(filters.Arg("label", label))}
+	for key, value := range scope {
+		options.Filters.Add("label", key+"="+value)
+	}
+	containers, err := p.clientDocker.ContainerList(ctx, options)
+	if err != nil {
+		return nil, logError(p.logs, err)
+	}
+
+	filtered := containers[:0]
+	for _, ct := range containers {
+		if project, ok := ct.Labels[ComposeProjectLabel]; ok && (name == "" || project == name) && scope.Matches(ct.Labels) {
+			filtered = append(filtered, ct)
+		}
+	}
+	return filtered, nil
+}
+
+// groupProjects groups containers by compose project and service, by name.
+func groupProjects(containers []types.Container) []models.Project {
+	projects := []models.Project{}
+	index := make(map[string]int)
+	for i := range containers {
+		ct := &containers[i]
+		name := ct.Labels[ComposeProjectLabel]
+		k, ok := index[name]
+		if !ok {
+			k = len(projects)
+			index[name] = k
+			projects = append(projects, models.Project{Name: name, Services: []models.ProjectService{}})
+		}
+		project := &projects[k]
+
+		serviceName := ct.Labels[ComposeServiceLabel]
+		var service *models.ProjectService
+		for s := range project.Services {
+			if project.Services[s].Name == serviceName {
+				service = &project.Services[s]
+			}
+		}
+		if service == nil {
+			project.Services = append(project.Services, models.ProjectService{Name: serviceName, Containers: []models.ProjectContainer{}})
+			service = &project.Services[len(project.Services)-1]
+		}
+
+		service.Containers = append(service.Containers, models.ProjectContainer{
+			ID:     ct.ID,
+			Name:   containerName(ct),
+			Image:  ct.Image,
+			State:  ct.State,
+			Status: ct.Status,
+		})
+		service.Total++
+		project.Total++
+		if ct.State == "running" {
+			service.Running++
+			project.Running++
+		}
+	}
+
+	for k := range projects {
+		project := &projects[k]
+		switch project.Running {
+		case project.Total:
+			project.Status = "running"
+		case 0:
+			project.Status = "exited"
+		default:
+			project.Status = "partial"
+		}
+		sort.Slice(project.Services, func(i, j int) bool { return project.Services[i].Name < project.Services[j].Name })
+		for s := range project.Services {
+			containers := project.Services[s].Containers
+			sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects
+}
+
+func containerName(ct *types.Container) string {
+	if len(ct.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(ct.Names[0], "/")
+}
+
+// projectFields returns the values of a project a filter key is checked against.
+func projectFields(project *models.Project, key string) []string {
+	switch key {
+	case "name":
+		return []string{project.Name}
+	case "status":
+		return []string{project.Status}
+	case "service":
+		services := make([]string, len(project.Services))
+		for i, service := range project.Services {
+			services[i] = service.Name
+		}
+		return services
+	}
+	return nil
+}
+
+// matchProjectSearch tells if every keyword appears in the name, a service or a container name of a project.
+func matchProjectSearch(project *models.Project, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	fields := append([]string{project.Name}, projectFields(project, "service")...)
+	for _, service := range project.Services {
+		for _, ct := range service.Containers {
+			fields = append(fields, ct.Name)
+		}
+	}
+	return matchSearch(keywords, fields...)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestProjects(t *testing.T) {
+	logs := zerolog.Nop()
+	engine := NewFakeEngine()
+	projects := NewServiceProject(engine, &logs)
+	ctx := context.Background()
+
+	if _, err := engine.NetworkCreate(ctx, "shop_default", network.CreateOptions{Labels: map[string]string{ComposeProjectLabel: "shop"}}); err != nil {
+		t.Fatal(err)
+	}
+	compose := func(name, service string, labels map[string]string) string {
+		labels[ComposeProjectLabel], labels[ComposeServiceLabel] = "shop", service
+		created, err := engine.ContainerCreate(ctx, &container.Config{Image: "nginx:latest", Labels: labels},
+			&container.HostConfig{NetworkMode: "shop_default"}, nil, nil, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created.ID
+	}
+	web1 := compose("shop-web-1", "web", map[string]string{"team": "a"})
+	compose("shop-web-2", "web", map[string]string{"team": "a"})
+	compose("shop-db-1", "db", map[string]string{})
+	if err := engine.ContainerStart(ctx, web1, container.StartOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := projects.List(ctx, &models.QueryParams{}, nil)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("the fake containers have no project, only shop expected, got %+v %v", list, err)
+	}
+	shop := list[0]
+	if shop.Name != "shop" || shop.Status != "partial" || shop.Running != 1 || shop.Total != 3 || len(shop.Services) != 2 ||
+		shop.Services[1].Name != "web" || shop.Services[1].Running != 1 || shop.Services[1].Total != 2 {
+		t.Errorf("shop should run one container of web out of three, got %+v", shop)
+	}
+	if filtered, _ := projects.List(ctx, &models.QueryParams{FilterClause: []string{"status:running"}}, nil); len(filtered) != 0 {
+		t.Errorf("shop is partially running, got %+v", filtered)
+	}
+	scoped, err := projects.Get(ctx, "shop", models.Scope{"team": "a"})
+	if err != nil || scoped.Total != 2 || len(scoped.Services) != 1 {
+		t.Errorf("a scoped caller should only see web, got %+v %v", scoped, err)
+	}
+	if _, err := projects.Get(ctx, "blog", nil); !errdefs.IsNotFound(err) {
+		t.Errorf("an unknown project should not be found, got %v", err)
+	}
+	if _, err := projects.Action(ctx, "shop", "pause", nil, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("an unknown action should be refused, got %v", err)
+	}
+
+	started, err := projects.Action(ctx, "shop", "start", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.Succeeded != 3 || started.Failed != 0 || started.Containers[0].Service != "db" || started.Containers[1].Name != "shop-web-1" || started.Containers[1].Result != "skipped" {
+		t.Errorf("db and shop-web-2 should start, shop-web-1 already runs, got %+v", started)
+	}
+	if shop, _ := projects.Get(ctx, "shop", nil); shop.Status != "running" {
+		t.Errorf("shop should be running, got %+v", shop)
+	}
+
+	down, err := projects.Action(ctx, "shop", "down", nil, models.Scope{"team": "a"})
+	if err != nil || down.Succeeded != 2 || len(down.Networks) != 0 {
+		t.Errorf("web should be removed and the network kept for db, got %+v %v", down, err)
+	}
+	down, err = projects.Action(ctx, "shop", "down", nil, nil)
+	if err != nil || down.Succeeded != 1 || len(down.Networks) != 1 || down.Networks[0] != "shop_default" {
+		t.Errorf("db then the network should be removed, got %+v %v", down, err)
+	}
+	if _, err := projects.Get(ctx, "shop", nil); !errdefs.IsNotFound(err) {
+		t.Errorf("shop should be gone, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// normalizeImage returns the familiar form of an image reference, tagged latest when untagged.
+func normalizeImage(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", errdefs.InvalidParameter(err)
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named)), nil
+}
+
+// pullImage pulls an image and calls progress, when set, for every message of the daemon.
+func pullImage(ctx context.Context, backend DockerBackend, ref string, progress func(models.PullProgress) error) error {
+	reader, err := backend.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		// Pull errors are sent in the stream with a 200 status
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if progress == nil {
+			continue
+		}
+		p := models.PullProgress{ID: msg.ID, Status: msg.Status, Progress: msg.ProgressMessage}
+		if msg.Progress != nil {
+			p.Current, p.Total = msg.Progress.Current, msg.Progress.Total
+		}
+		if err := progress(p); err != nil {
+			return err
+		}
+	}
+}
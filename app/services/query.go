@@ -0,0 +1,351 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+)
+
+// containerFilterKeys maps the filter keys of the list endpoint to the daemon filter
+// they are pushed down to. Empty when the daemon has no equivalent.
+var containerFilterKeys = map[string]string{
+	"id":      "id",
+	"name":    "name",
+	"image":   "ancestor",
+	"label":   "label",
+	"network": "network",
+	"state":   "status",
+	"status":  "",
+}
+
+// parseClauses reads "key:value" filters, grouped by key.
+func parseClauses(raw []string, accepted map[string]string) (map[string][]string, error) {
+	clauses := make(map[string][]string)
+	for _, r := range raw {
+		key, value, ok := strings.Cut(r, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || value == "" {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("invalid filter %q, expected key:value", r))
+		}
+		if _, ok := accepted[key]; !ok {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("unknown filter key %q", key))
+		}
+		clauses[key] = append(clauses[key], value)
+	}
+	return clauses, nil
+}
+
+// List returns the containers of scope matching the filters, filter_like and search
+// clauses of params, sorted by its sort clause.
+func (c *Container) List(ctx context.Context, params *models.QueryParams, scope models.Scope) ([]types.Container, error) {
+	exact, err := parseClauses(params.FilterClause, containerFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	like, err := parseClauses(params.FilterLikeClause, containerFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Push the exact filters down to the daemon, they are checked again below
+	// as the daemon is more lenient (name is a regexp, ancestor includes children).
+	options := container.ListOptions{All: params.All, Filters: filters.NewArgs()}
+	for key, values := range exact {
+		dockerKey := containerFilterKeys[key]
+		if dockerKey == "" {
+			continue
+		}
+		for _, value := range values {
+			if key == "state" && value != "running" {
+				options.All = true
+			}
+			options.Filters.Add(dockerKey, value)
+		}
+	}
+	// The daemon requires all the label filters, as a scope does
+	for key, value := range scope {
+		options.Filters.Add("label", key+"="+value)
+	}
+
+	containers, err := c.clientDocker.ContainerList(ctx, options)
+	if err != nil {
+		return nil, logError(c.logs, err)
+	}
+
+	filtered := containers[:0]
+	for i := range containers {
+		ct := &containers[i]
+		fields := func(key string) []string { return containerFields(ct, key) }
+		if scope.Matches(ct.Labels) && matchClauses(exact, false, fields, matchContainerField) && matchClauses(like, true, fields, matchContainerField) && matchContainerSearch(ct, params.SearchClause) {
+			filtered = append(filtered, *ct)
+		}
+	}
+
+	sortKeys := make([]string, len(params.SortClause))
+	for i, key := range params.SortClause {
+		// Names is the JSON field, name is accepted like for filters
+		if strings.EqualFold(strings.TrimPrefix(key, "-"), "name") {
+			if strings.HasPrefix(key, "-") {
+				key = "-Names"
+			} else {
+				key = "Names"
+			}
+		}
+		sortKeys[i] = key
+	}
+	if err := sortItems(filtered, sortKeys); err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+func matchContainerField(key, field, value string) bool {
+	switch key {
+	case "id", "status":
+		return strings.HasPrefix(field, value)
+	case "label":
+		return matchLabel(field, value)
+	case "image":
+		if normalized, err := normalizeImage(value); err == nil && strings.EqualFold(field, normalized) {
+			return true
+		}
+		return field == value
+	default:
+		return field == value
+	}
+}
+
+// containerFields returns the values of a container a filter key is checked against.
+func containerFields(c *types.Container, key string) []string {
+	switch key {
+	case "id":
+		return []string{c.ID}
+	case "name":
+		names := make([]string, len(c.Names))
+		for i, name := range c.Names {
+			names[i] = strings.TrimPrefix(name, "/")
+		}
+		return names
+	case "image":
+		image := c.Image
+		if normalized, err := normalizeImage(image); err == nil {
+			image = normalized
+		}
+		return []string{image, c.ImageID}
+	case "label":
+		return labelFields(c.Labels)
+	case "network":
+		networks := []string{}
+		if c.NetworkSettings != nil {
+			for name := range c.NetworkSettings.Networks {
+				networks = append(networks, name)
+			}
+		}
+		return networks
+	case "state":
+		return []string{c.State}
+	case "status":
+		return []string{c.Status}
+	}
+	return nil
+}
+
+// matchContainerSearch tells if every keyword appears in the ID, a name, the image,
+// the state, the status, the command or a label of a container.
+func matchContainerSearch(c *types.Container, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	fields := []string{c.ID, c.Image, c.State, c.Status, c.Command}
+	fields = append(fields, containerFields(c, "name")...)
+	fields = append(fields, containerFields(c, "label")...)
+	return matchSearch(keywords, fields...)
+}
+
+// matchClauses tells if an item matches all the keys of clauses, and one of the values
+// of each key. fields returns the values of the item a key is checked against, exact
+// compares one of them to a value of an exact clause while a like clause only needs
+// to be a part of it. Fields and values are lowercased first.
+func matchClauses(clauses map[string][]string, like bool, fields func(key string) []string, exact func(key, field, value string) bool) bool {
+	for key, values := range clauses {
+		matched := false
+		for _, field := range fields(key) {
+			field = strings.ToLower(field)
+			for _, value := range values {
+				value = strings.ToLower(value)
+				if (like && strings.Contains(field, value)) || (!like && exact(key, field, value)) {
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchEqual is the exact matcher of the items without a rule of their own.
+func matchEqual(_, field, value string) bool {
+	return field == value
+}
+
+// matchLabel matches a "key=value" label field: "key" matches any value, "key=value" an exact one.
+func matchLabel(field, value string) bool {
+	if !strings.Contains(value, "=") {
+		k, _, _ := strings.Cut(field, "=")
+		return k == value
+	}
+	return field == value
+}
+
+// labelFields returns labels as "key=value" fields.
+func labelFields(labels map[string]string) []string {
+	fields := make([]string, 0, len(labels))
+	for k, v := range labels {
+		fields = append(fields, k+"="+v)
+	}
+	return fields
+}
+
+// matchSearch tells if every keyword appears in one of fields.
+func matchSearch(keywords []string, fields ...string) bool {
+	haystack := strings.ToLower(strings.Join(fields, "\n"))
+	for _, keyword := range keywords {
+		// Parse doubles apostrophes for SQL, undo it
+		keyword = strings.ReplaceAll(strings.ToLower(keyword), "''", "'")
+		if keyword != "" && !strings.Contains(haystack, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// boolClauses checks the values of a true/false filter key, and writes them as
+// "true" or "false" so that they compare as strings.
+func boolClauses(clauses map[string][]string, key string) error {
+	for i, value := range clauses[key] {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errdefs.InvalidParameter(fmt.Errorf("invalid %s filter %q, true or false expected", key, value))
+		}
+		clauses[key][i] = strconv.FormatBool(b)
+	}
+	return nil
+}
+
+// sortItems sorts a slice of structs on their JSON fields (dot paths), a "-" prefix sorting descending.
+func sortItems[T any](items []T, keys []string) error {
+	if len(keys) == 0 || len(items) < 2 {
+		return nil
+	}
+
+	objects := make([]map[string]interface{}, len(items))
+	for i := range items {
+		obj, err := functions.ToMap(items[i])
+		if err != nil {
+			return err
+		}
+		objects[i] = obj
+	}
+
+	index := make([]int, len(items))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		for _, key := range keys {
+			desc := strings.HasPrefix(key, "-")
+			path := strings.TrimPrefix(key, "-")
+			a, _ := functions.GetPath(objects[index[i]], path)
+			b, _ := functions.GetPath(objects[index[j]], path)
+			cmp := functions.CompareValues(a, b)
+			if cmp == 0 {
+				continue
+			}
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	sorted := make([]T, len(items))
+	for i, k := range index {
+		sorted[i] = items[k]
+	}
+	copy(items, sorted)
+	return nil
+}
+
+// Compose labels, set on every container created by docker compose
+const (
+	ComposeProjectLabel = "com.docker.compose.project"
+	ComposeServiceLabel = "com.docker.compose.service"
+)
+
+// GroupContainers counts the containers per value of a column: a JSON field or dot path
+// ("State", "HostConfig.NetworkMode"), a label ("Labels.team" or just "team"),
+// or "project" and "service" for compose labels. Groups are sorted by decreasing count.
+func GroupContainers(containers []types.Container, col string, members bool) ([]models.Group, error) {
+	groups := []models.Group{}
+	index := make(map[string]int)
+
+	for i := range containers {
+		value, err := groupValue(&containers[i], col)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := index[value]
+		if !ok {
+			k = len(groups)
+			index[value] = k
+			groups = append(groups, models.Group{Value: value})
+		}
+		groups[k].Count++
+		if members {
+			groups[k].IDs = append(groups[k].IDs, containers[i].ID)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Value < groups[j].Value
+	})
+	return groups, nil
+}
+
+func groupValue(c *types.Container, col string) (string, error) {
+	switch lower := strings.ToLower(col); {
+	case lower == "project":
+		return c.Labels[ComposeProjectLabel], nil
+	case lower == "service":
+		return c.Labels[ComposeServiceLabel], nil
+	case lower == "name", lower == "names":
+		return strings.TrimPrefix(functions.ValueString(c.Names), "/"), nil
+	case strings.HasPrefix(lower, "labels."):
+		// Label keys hold dots, they are not a path
+		return c.Labels[col[len("labels."):]], nil
+	}
+
+	obj, err := functions.ToMap(c)
+	if err != nil {
+		return "", err
+	}
+	if value, ok := functions.GetPath(obj, col); ok {
+		return functions.ValueString(value), nil
+	}
+	return c.Labels[col], nil
+}
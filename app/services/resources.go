@@ -0,0 +1,125 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Resources returns a sample of the resources used by a container.
+func (c *Container) Resources(ctx context.Context, ref string) (models.Resources, error) {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return models.Resources{}, err
+	}
+	return c.sample(ctx, inspect.ID)
+}
+
+// sample returns a sample of the resources used by the container of id, resolved already.
+func (c *Container) sample(ctx context.Context, id string) (models.Resources, error) {
+	stats, err := c.clientDocker.ContainerStats(ctx, id, false)
+	if err != nil {
+		return models.Resources{}, logError(c.logs, err)
+	}
+	defer stats.Body.Close()
+
+	var sample container.StatsResponse
+	if err := json.NewDecoder(stats.Body).Decode(&sample); err != nil {
+		return models.Resources{}, logError(c.logs, err)
+	}
+	return ComputeResources(&sample), nil
+}
+
+// StreamResources sends a sample of the resources used by a container every interval
+// until ctx is done or the container stops.
+func (c *Container) StreamResources(ctx context.Context, ref string, interval time.Duration, send func(models.Resources) error) error {
+	inspect, err := c.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	stats, err := c.clientDocker.ContainerStats(ctx, inspect.ID, true)
+	if err != nil {
+		return logError(c.logs, err)
+	}
+	defer stats.Body.Close()
+
+	decoder := json.NewDecoder(stats.Body)
+	var last time.Time
+	for {
+		var sample container.StatsResponse
+		if err := decoder.Decode(&sample); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return logError(c.logs, err)
+		}
+		// The daemon samples about every second, skip samples until the interval is elapsed
+		if !last.IsZero() && sample.Read.Sub(last) < interval-100*time.Millisecond {
+			continue
+		}
+		last = sample.Read
+		if err := send(ComputeResources(&sample)); err != nil {
+			return err
+		}
+	}
+}
+
+// ComputeResources converts raw daemon stats, the same way the docker CLI does.
+func ComputeResources(stats *container.StatsResponse) models.Resources {
+	resources := models.Resources{
+		ID:          stats.ID,
+		Name:        strings.TrimPrefix(stats.Name, "/"),
+		Read:        stats.Read,
+		OnlineCPUs:  stats.CPUStats.OnlineCPUs,
+		MemoryLimit: stats.MemoryStats.Limit,
+		PIDs:        stats.PidsStats.Current,
+	}
+	if resources.OnlineCPUs == 0 {
+		resources.OnlineCPUs = uint32(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	// CPU: container usage delta over host usage delta between the previous and current samples
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		resources.CPUPercent = functions.Round(cpuDelta/systemDelta*float64(resources.OnlineCPUs)*100, 0.5, 2)
+	}
+
+	// Memory: page cache can be reclaimed, it is not counted (cgroup v1 then v2 key)
+	resources.MemoryUsage = stats.MemoryStats.Usage
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, ok := stats.MemoryStats.Stats[key]; ok {
+			if cache < resources.MemoryUsage {
+				resources.MemoryUsage -= cache
+			}
+			break
+		}
+	}
+	if resources.MemoryLimit > 0 {
+		resources.MemoryPercent = functions.Round(float64(resources.MemoryUsage)/float64(resources.MemoryLimit)*100, 0.5, 2)
+	}
+
+	for _, network := range stats.Networks {
+		resources.NetworkRx += network.RxBytes
+		resources.NetworkTx += network.TxBytes
+	}
+
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			resources.BlockRead += entry.Value
+		case "write":
+			resources.BlockWrite += entry.Value
+		}
+	}
+
+	return resources
+}
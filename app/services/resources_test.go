@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rs/zerolog"
+)
+
+func TestComputeResources(t *testing.T) {
+	stats := &container.StatsResponse{Name: "/nginx", ID: "abc"}
+	stats.PreCPUStats = container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 1000}, SystemUsage: 100000}
+	stats.CPUStats = container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 3500}, SystemUsage: 200000, OnlineCPUs: 4}
+	stats.MemoryStats = container.MemoryStats{
+		Usage: 60 * 1024 * 1024,
+		Limit: 512 * 1024 * 1024,
+		Stats: map[string]uint64{"total_inactive_file": 10 * 1024 * 1024},
+	}
+	stats.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: 100, TxBytes: 10},
+		"eth1": {RxBytes: 50, TxBytes: 5},
+	}
+	stats.BlkioStats.IoServiceBytesRecursive = []container.BlkioStatEntry{
+		{Op: "Read", Value: 7},
+		{Op: "Write", Value: 3},
+		{Op: "Total", Value: 10},
+	}
+
+	resources := ComputeResources(stats)
+	if resources.Name != "nginx" {
+		t.Errorf("name should not start with a slash, got %s", resources.Name)
+	}
+	if resources.CPUPercent != 10 {
+		t.Errorf("expected 10%% CPU, got %v", resources.CPUPercent)
+	}
+	if resources.MemoryUsage != 50*1024*1024 || resources.MemoryPercent != 9.77 {
+		t.Errorf("unexpected memory %d (%v%%)", resources.MemoryUsage, resources.MemoryPercent)
+	}
+	if resources.NetworkRx != 150 || resources.NetworkTx != 15 {
+		t.Errorf("unexpected network %d/%d", resources.NetworkRx, resources.NetworkTx)
+	}
+	if resources.BlockRead != 7 || resources.BlockWrite != 3 {
+		t.Errorf("unexpected block I/O %d/%d", resources.BlockRead, resources.BlockWrite)
+	}
+
+	// First sample of a stream has no previous CPU values
+	stats.PreCPUStats = container.CPUStats{}
+	stats.CPUStats.SystemUsage = 0
+	if resources := ComputeResources(stats); resources.CPUPercent != 0 {
+		t.Errorf("expected 0%% CPU without system delta, got %v", resources.CPUPercent)
+	}
+}
+
+func TestStatsCollectorWindow(t *testing.T) {
+	c, _ := newTestContainer()
+	logs := zerolog.Nop()
+	collector := NewStatsCollector(c, time.Second, 2*time.Second, &logs)
+
+	for i := 0; i < 3; i++ {
+		collector.collect(context.Background())
+	}
+
+	inspect, err := c.Inspect(context.Background(), "fake-nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history := collector.History(inspect.ID, time.Time{}); len(history) != 2 {
+		t.Errorf("history should keep 2 samples, got %d", len(history))
+	}
+	if history := collector.History(inspect.ID, time.Now().Add(time.Minute)); len(history) != 0 {
+		t.Errorf("no sample should be newer than since, got %d", len(history))
+	}
+}
+
+func TestStatsCollectorShortRetention(t *testing.T) {
+	c, _ := newTestContainer()
+	logs := zerolog.Nop()
+	collector := NewStatsCollector(c, 10*time.Second, time.Second, &logs)
+
+	for i := 0; i < 2; i++ {
+		collector.collect(context.Background())
+	}
+
+	inspect, err := c.Inspect(context.Background(), "fake-nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history := collector.History(inspect.ID, time.Time{}); len(history) != 1 {
+		t.Errorf("a retention shorter than the interval should keep the last sample, got %d", len(history))
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+)
+
+// Same rules as the daemon for container and volume names
+var (
+	containerNameRegexp = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+	volumeNameRegexp    = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+)
+
+// AuthorizeBinds checks that a scoped caller only mounts the volumes of its scope.
+// Host paths would give access to the host, the Docker socket for instance.
+func (c *Container) AuthorizeBinds(ctx context.Context, binds []string, scope models.Scope) error {
+	if len(scope) == 0 {
+		return nil
+	}
+	for _, bind := range binds {
+		source, _, _ := strings.Cut(bind, ":")
+		if !volumeNameRegexp.MatchString(source) {
+			return errdefs.Forbidden(fmt.Errorf("bind %q mounts a host path, only the volumes of your scope can be mounted", bind))
+		}
+		// Volumes out of the scope are reported as missing so that their existence is not disclosed
+		vol, err := c.clientDocker.VolumeInspect(ctx, source)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return logError(c.logs, err)
+		}
+		if err != nil || !scope.Matches(vol.Labels) {
+			return errdefs.Forbidden(fmt.Errorf("no volume %s in your scope, create it before mounting it", source))
+		}
+	}
+	return nil
+}
+
+// Run creates a container from spec and starts it unless spec.Start is false.
+// The image is pulled when it is missing, progress is called for every pull message.
+func (c *Container) Run(ctx context.Context, spec *models.ContainerSpec, progress func(models.PullProgress) error) (models.ContainerCreated, error) {
+	if err := c.validate.Struct(spec); err != nil {
+		return models.ContainerCreated{}, errdefs.InvalidParameter(err)
+	}
+	if spec.Name != "" && !containerNameRegexp.MatchString(spec.Name) {
+		return models.ContainerCreated{}, errdefs.InvalidParameter(fmt.Errorf("invalid container name %q, only [a-zA-Z0-9][a-zA-Z0-9_.-] are allowed", spec.Name))
+	}
+
+	ref, err := normalizeImage(spec.Image)
+	if err != nil {
+		return models.ContainerCreated{}, err
+	}
+	config, hostConfig, err := containerConfig(spec, ref)
+	if err != nil {
+		return models.ContainerCreated{}, err
+	}
+
+	created := models.ContainerCreated{Image: ref, Warnings: []string{}}
+	if created.Pulled, err = c.ensureImage(ctx, ref, spec.Pull, progress); err != nil {
+		return created, logError(c.logs, err)
+	}
+
+	response, err := c.clientDocker.ContainerCreate(ctx, config, hostConfig, nil, nil, spec.Name)
+	if err != nil {
+		return created, logError(c.logs, err)
+	}
+	created.ID = response.ID
+	created.Warnings = append(created.Warnings, response.Warnings...)
+
+	if spec.Start == nil || *spec.Start {
+		if err := c.clientDocker.ContainerStart(ctx, response.ID, container.StartOptions{}); err != nil {
+			return created, logError(c.logs, err)
+		}
+		created.Started = true
+	}
+
+	inspect, err := c.clientDocker.ContainerInspect(ctx, response.ID)
+	if err == nil && inspect.ContainerJSONBase != nil {
+		created.Name = strings.TrimPrefix(inspect.Name, "/")
+	}
+	return created, nil
+}
+
+// ensureImage pulls ref according to the pull policy and tells if it was pulled.
+func (c *Container) ensureImage(ctx context.Context, ref string, policy string, progress func(models.PullProgress) error) (bool, error) {
+	if policy != "always" {
+		_, _, err := c.clientDocker.ImageInspectWithRaw(ctx, ref)
+		if err == nil {
+			return false, nil
+		}
+		if !errdefs.IsNotFound(err) {
+			return false, err
+		}
+		if policy == "never" {
+			return false, err
+		}
+	}
+
+	c.logs.Info().Str("image", ref).Msg("Pulling image")
+	if err := pullImage(ctx, c.clientDocker, ref, progress); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// containerConfig converts a spec to the daemon configuration.
+func containerConfig(spec *models.ContainerSpec, ref string) (*container.Config, *container.HostConfig, error) {
+	exposed, bindings, err := nat.ParsePortSpecs(spec.Ports)
+	if err != nil {
+		return nil, nil, errdefs.InvalidParameter(err)
+	}
+
+	config := &container.Config{
+		Image:        ref,
+		Cmd:          spec.Command,
+		Env:          envList(spec.Env),
+		Labels:       spec.Labels,
+		ExposedPorts: exposed,
+	}
+	hostConfig := &container.HostConfig{
+		Binds:        spec.Volumes,
+		PortBindings: bindings,
+		RestartPolicy: container.RestartPolicy{
+			Name:              container.RestartPolicyMode(spec.RestartPolicy),
+			MaximumRetryCount: spec.RestartMaxRetries,
+		},
+		Resources: container.Resources{
+			Memory:   spec.Memory,
+			NanoCPUs: int64(spec.CPUs * 1e9),
+		},
+	}
+	return config, hostConfig, nil
+}
+
+// envList converts environment variables to the KEY=value form of the daemon, sorted.
+func envList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for k, v := range env {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return list
+}
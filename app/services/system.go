@@ -0,0 +1,343 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+)
+
+// anonymousVolumeLabel is set by the daemon on the volumes created without a name.
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// isAnonymousVolume tells if a volume was created without a name.
+func isAnonymousVolume(v *volume.Volume) bool {
+	_, ok := v.Labels[anonymousVolumeLabel]
+	return ok
+}
+
+// isPredefinedNetwork tells if a network is one of the networks the daemon creates.
+func isPredefinedNetwork(name string) bool {
+	return name == "bridge" || name == "host" || name == "none"
+}
+
+type System struct {
+	clientDocker DockerBackend
+	validate     *validator.Validate
+	logs         *zerolog.Logger
+}
+
+func NewServiceSystem(backend DockerBackend, logs *zerolog.Logger) *System {
+	return &System{
+		clientDocker: backend,
+		validate:     validator.New(),
+		logs:         logs,
+	}
+}
+
+// Prune removes the unused resources selected by spec, containers first so that the
+// resources they used are pruned with them. With dryRun nothing is removed, the report
+// tells what would be and the space it would reclaim.
+func (s *System) Prune(ctx context.Context, spec *models.PruneSpec, dryRun bool) (models.PruneReport, error) {
+	if err := s.validate.Struct(spec); err != nil {
+		return models.PruneReport{}, errdefs.InvalidParameter(err)
+	}
+	if !spec.Containers && spec.Images == "" && !spec.Networks && spec.Volumes == "" && !spec.BuildCache {
+		return models.PruneReport{}, errdefs.InvalidParameter(errors.New("nothing to prune, select containers, images, networks, volumes or build_cache"))
+	}
+	// Build cache records have no labels, the daemon refuses the filter
+	if spec.BuildCache && len(spec.Labels) > 0 {
+		return models.PruneReport{}, errdefs.InvalidParameter(errors.New("build cache cannot be pruned with label filters"))
+	}
+
+	args := filters.NewArgs()
+	if spec.Until != "" {
+		args.Add("until", spec.Until)
+	}
+	for _, label := range spec.Labels {
+		if strings.HasPrefix(label, "!") {
+			args.Add("label!", label[1:])
+		} else {
+			args.Add("label", label)
+		}
+	}
+	filter, err := newPruneFilter(args)
+	if err != nil {
+		return models.PruneReport{}, err
+	}
+
+	var report models.PruneReport
+	if dryRun {
+		report, err = s.preview(ctx, spec, filter)
+	} else {
+		report, err = s.prune(ctx, spec, args, filter)
+	}
+	if err != nil {
+		return report, logError(s.logs, err)
+	}
+	for _, result := range []*models.PruneResult{report.Containers, report.Images, report.Networks, report.Volumes, report.BuildCache} {
+		if result != nil {
+			sort.Strings(result.Deleted)
+			report.SpaceReclaimed += result.SpaceReclaimed
+		}
+	}
+	return report, nil
+}
+
+// prune removes the resources with the prune endpoints of the daemon, but the volumes:
+// the daemon has no until filter for them, they are removed one by one.
+func (s *System) prune(ctx context.Context, spec *models.PruneSpec, args filters.Args, filter pruneFilter) (models.PruneReport, error) {
+	report := models.PruneReport{}
+
+	if spec.Containers {
+		pruned, err := s.clientDocker.ContainersPrune(ctx, args)
+		if err != nil {
+			return report, err
+		}
+		report.Containers = &models.PruneResult{Deleted: append([]string{}, pruned.ContainersDeleted...), SpaceReclaimed: pruned.SpaceReclaimed}
+	}
+
+	if spec.Images != "" {
+		imageArgs := args.Clone()
+		imageArgs.Add("dangling", strconv.FormatBool(spec.Images == "dangling"))
+		pruned, err := s.clientDocker.ImagesPrune(ctx, imageArgs)
+		if err != nil {
+			return report, err
+		}
+		report.Images = &models.PruneResult{Deleted: []string{}, SpaceReclaimed: pruned.SpaceReclaimed}
+		for _, deleted := range pruned.ImagesDeleted {
+			if deleted.Deleted != "" {
+				report.Images.Deleted = append(report.Images.Deleted, deleted.Deleted)
+			}
+		}
+	}
+
+	if spec.Networks {
+		pruned, err := s.clientDocker.NetworksPrune(ctx, args)
+		if err != nil {
+			return report, err
+		}
+		report.Networks = &models.PruneResult{Deleted: append([]string{}, pruned.NetworksDeleted...)}
+	}
+
+	if spec.Volumes != "" {
+		usage, err := s.clientDocker.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.ContainerObject, types.VolumeObject}})
+		if err != nil {
+			return report, err
+		}
+		_, _, used := usedResources(usage.Containers, nil)
+		report.Volumes = &models.PruneResult{Deleted: []string{}}
+		for _, v := range unusedVolumes(usage.Volumes, used, spec.Volumes == "all", filter) {
+			if err := s.clientDocker.VolumeRemove(ctx, v.Name, false); err != nil {
+				// A volume mounted or removed since the usage was computed is skipped
+				if errdefs.IsConflict(err) || errdefs.IsNotFound(err) {
+					continue
+				}
+				return report, err
+			}
+			report.Volumes.Deleted = append(report.Volumes.Deleted, v.Name)
+			report.Volumes.SpaceReclaimed += volumeSize(v)
+		}
+	}
+
+	if spec.BuildCache {
+		cacheArgs := filters.NewArgs()
+		if spec.Until != "" {
+			cacheArgs.Add("until", spec.Until)
+		}
+		pruned, err := s.clientDocker.BuildCachePrune(ctx, types.BuildCachePruneOptions{All: true, Filters: cacheArgs})
+		if err != nil {
+			return report, err
+		}
+		report.BuildCache = &models.PruneResult{Deleted: []string{}}
+		if pruned != nil {
+			report.BuildCache.Deleted = append(report.BuildCache.Deleted, pruned.CachesDeleted...)
+			report.BuildCache.SpaceReclaimed = pruned.SpaceReclaimed
+		}
+	}
+	return report, nil
+}
+
+// preview computes the resources a prune would remove from the disk usage of the daemon,
+// the resources of the containers pruned being unused.
+func (s *System) preview(ctx context.Context, spec *models.PruneSpec, filter pruneFilter) (models.PruneReport, error) {
+	report := models.PruneReport{DryRun: true}
+	usage, err := s.clientDocker.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return report, err
+	}
+
+	pruned := make(map[string]bool)
+	if spec.Containers {
+		report.Containers = &models.PruneResult{Deleted: []string{}}
+		for _, c := range usage.Containers {
+			if c.State == "running" || c.State == "paused" || c.State == "restarting" || !filter.match(time.Unix(c.Created, 0), c.Labels) {
+				continue
+			}
+			pruned[c.ID] = true
+			report.Containers.Deleted = append(report.Containers.Deleted, c.ID)
+			report.Containers.SpaceReclaimed += uint64(max(c.SizeRw, 0))
+		}
+	}
+	usedImages, usedNetworks, usedVolumes := usedResources(usage.Containers, pruned)
+
+	if spec.Images != "" {
+		report.Images = &models.PruneResult{Deleted: []string{}}
+		for _, img := range usage.Images {
+			if usedImages[img.ID] || (spec.Images == "dangling" && !isDanglingImage(img.RepoTags)) || !filter.match(time.Unix(img.Created, 0), img.Labels) {
+				continue
+			}
+			report.Images.Deleted = append(report.Images.Deleted, img.ID)
+			report.Images.SpaceReclaimed += uint64(max(img.Size-max(img.SharedSize, 0), 0))
+		}
+	}
+
+	if spec.Networks {
+		networks, err := s.clientDocker.NetworkList(ctx, network.ListOptions{})
+		if err != nil {
+			return report, err
+		}
+		report.Networks = &models.PruneResult{Deleted: []string{}}
+		for _, n := range networks {
+			if isPredefinedNetwork(n.Name) || n.Scope == "swarm" || usedNetworks[n.Name] || !filter.match(n.Created, n.Labels) {
+				continue
+			}
+			report.Networks.Deleted = append(report.Networks.Deleted, n.Name)
+		}
+	}
+
+	if spec.Volumes != "" {
+		report.Volumes = &models.PruneResult{Deleted: []string{}}
+		for _, v := range unusedVolumes(usage.Volumes, usedVolumes, spec.Volumes == "all", filter) {
+			report.Volumes.Deleted = append(report.Volumes.Deleted, v.Name)
+			report.Volumes.SpaceReclaimed += volumeSize(v)
+		}
+	}
+
+	if spec.BuildCache {
+		report.BuildCache = &models.PruneResult{Deleted: []string{}}
+		for _, record := range usage.BuildCache {
+			if record.InUse || !filter.match(lastUsed(record), nil) {
+				continue
+			}
+			report.BuildCache.Deleted = append(report.BuildCache.Deleted, record.ID)
+			report.BuildCache.SpaceReclaimed += uint64(max(record.Size, 0))
+		}
+	}
+	return report, nil
+}
+
+// usedResources returns the images, networks and volumes used by the containers but the pruned ones.
+func usedResources(containers []*types.Container, pruned map[string]bool) (images, networks, volumes map[string]bool) {
+	images, networks, volumes = make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, c := range containers {
+		if pruned[c.ID] {
+			continue
+		}
+		images[c.ImageID] = true
+		if c.NetworkSettings != nil {
+			for name := range c.NetworkSettings.Networks {
+				networks[name] = true
+			}
+		}
+		for _, m := range c.Mounts {
+			if m.Name != "" {
+				volumes[m.Name] = true
+			}
+		}
+	}
+	return images, networks, volumes
+}
+
+// unusedVolumes returns the volumes not used, anonymous unless all, matching filter.
+func unusedVolumes(volumes []*volume.Volume, used map[string]bool, all bool, filter pruneFilter) []*volume.Volume {
+	unused := []*volume.Volume{}
+	for _, v := range volumes {
+		if used[v.Name] || (!all && !isAnonymousVolume(v)) {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, v.CreatedAt)
+		// A volume of unknown age is never older than until
+		if err != nil && !filter.until.IsZero() {
+			continue
+		}
+		if !filter.match(created, v.Labels) {
+			continue
+		}
+		unused = append(unused, v)
+	}
+	return unused
+}
+
+func volumeSize(v *volume.Volume) uint64 {
+	if v.UsageData == nil || v.UsageData.Size < 0 {
+		return 0
+	}
+	return uint64(v.UsageData.Size)
+}
+
+// lastUsed returns when a build cache record was last used, or created when never used.
+func lastUsed(record *types.BuildCache) time.Time {
+	if record.LastUsedAt != nil {
+		return *record.LastUsedAt
+	}
+	return record.CreatedAt
+}
+
+// pruneFilter holds the until and label filters of a prune, read like the daemon does.
+type pruneFilter struct {
+	until     time.Time
+	labels    []string
+	notLabels []string
+}
+
+func newPruneFilter(args filters.Args) (pruneFilter, error) {
+	filter := pruneFilter{labels: args.Get("label"), notLabels: args.Get("label!")}
+	if until := args.Get("until"); len(until) > 0 {
+		if len(until) > 1 {
+			return filter, errdefs.InvalidParameter(errors.New("more than one until filter specified"))
+		}
+		var err error
+		if filter.until, err = logTime(until[0]); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// match tells if a resource created at created, with labels, is selected: created before
+// until, holding all the labels and none of the excluded ones.
+func (p pruneFilter) match(created time.Time, labels map[string]string) bool {
+	if !p.until.IsZero() && !created.Before(p.until) {
+		return false
+	}
+	for _, label := range p.labels {
+		if !hasLabel(labels, label) {
+			return false
+		}
+	}
+	for _, label := range p.notLabels {
+		if hasLabel(labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLabel tells if labels hold a "key" or a "key=value" label.
+func hasLabel(labels map[string]string, label string) bool {
+	key, value, withValue := strings.Cut(label, "=")
+	v, ok := labels[key]
+	return ok && (!withValue || v == value)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestPrune(t *testing.T) {
+	logs := zerolog.Nop()
+	engine := NewFakeEngine()
+	system := NewServiceSystem(engine, &logs)
+	ctx := context.Background()
+
+	job, err := engine.ContainerCreate(ctx, &container.Config{
+		Image:   "nginx:latest",
+		Labels:  map[string]string{"ci": "true"},
+		Volumes: map[string]struct{}{"/cache": {}},
+	}, &container.HostConfig{Binds: []string{"reports:/reports"}}, nil, nil, "ci-job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.mu.Lock()
+	engine.addImage("alpine:3.20", 8*1024*1024)
+	engine.addNetwork("ci", "bridge", map[string]string{"ci": "true"})
+	engine.mu.Unlock()
+
+	if _, err := system.Prune(ctx, &models.PruneSpec{}, true); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("an empty prune should be refused, got %v", err)
+	}
+	if _, err := system.Prune(ctx, &models.PruneSpec{BuildCache: true, Labels: []string{"ci"}}, true); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("build cache has no labels, got %v", err)
+	}
+	if _, err := system.Prune(ctx, &models.PruneSpec{Containers: true, Until: "yesterday"}, true); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("until should be a duration or a timestamp, got %v", err)
+	}
+
+	labelled, err := system.Prune(ctx, &models.PruneSpec{Containers: true, Networks: true, Labels: []string{"ci=true"}}, true)
+	if err != nil || !reflect.DeepEqual(labelled.Containers.Deleted, []string{job.ID}) || len(labelled.Networks.Deleted) != 1 {
+		t.Errorf("label filters should select the CI job and network, got %+v %v", labelled, err)
+	}
+	excluded, _ := system.Prune(ctx, &models.PruneSpec{Containers: true, Labels: []string{"!ci"}}, true)
+	if len(excluded.Containers.Deleted) != 1 || excluded.Containers.Deleted[0] == job.ID {
+		t.Errorf("excluded label should keep the CI job, got %+v", excluded.Containers)
+	}
+	old, _ := system.Prune(ctx, &models.PruneSpec{Containers: true, Images: "all", Until: "24h"}, true)
+	if len(old.Containers.Deleted) != 0 || len(old.Images.Deleted) != 1 {
+		t.Errorf("until should only select the images pulled two weeks ago, got %+v", old)
+	}
+
+	spec := &models.PruneSpec{Containers: true, Images: "all", Networks: true, Volumes: "anonymous", BuildCache: true}
+	preview, err := system.Prune(ctx, spec, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Containers.Deleted) != 2 || len(preview.Images.Deleted) != 2 || len(preview.Networks.Deleted) != 1 ||
+		len(preview.Volumes.Deleted) != 1 || len(preview.BuildCache.Deleted) != 3 || preview.SpaceReclaimed == 0 {
+		t.Errorf("the stopped containers and what only they use expected, got %+v", preview)
+	}
+	if _, err := engine.ContainerInspect(ctx, job.ID); err != nil {
+		t.Errorf("a dry run should not remove anything, got %v", err)
+	}
+
+	report, err := system.Prune(ctx, spec, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview.DryRun = false
+	if !reflect.DeepEqual(report, preview) {
+		t.Errorf("the prune should remove what the dry run told\n got %+v\nwant %+v", report, preview)
+	}
+	if _, err := engine.ContainerInspect(ctx, "fake-nginx"); err != nil {
+		t.Errorf("running containers should be kept, got %v", err)
+	}
+	if _, _, err := engine.ImageInspectWithRaw(ctx, "redis"); !errdefs.IsNotFound(err) {
+		t.Errorf("the image of the pruned redis should be removed, got %v", err)
+	}
+	if err := engine.VolumeRemove(ctx, "reports", false); err != nil {
+		t.Errorf("named volumes should be kept without all, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrTokenExpired is returned for a token past its expiration time.
+	ErrTokenExpired = errors.New("token is expired")
+	// ErrTokenInvalid is returned for a malformed token or a wrong signature.
+	ErrTokenInvalid = errors.New("token is invalid")
+)
+
+// ErrTokenSigning is returned when tokens cannot be issued with a public key.
+var ErrTokenSigning = errors.New("TOKEN_KEY is a public key, tokens cannot be issued")
+
+// Tokens signs and verifies the JWT of the API with TOKEN_KEY.
+// The key is an HS256 secret, or an RS256 key in PEM format, given inline or as a file path.
+// Tokens can only be verified, not issued, with an RSA public key.
+type Tokens struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewTokens parses the token key.
+func NewTokens(key string) (*Tokens, error) {
+	if key == "" {
+		return nil, errors.New("TOKEN_KEY is required")
+	}
+
+	pem := key
+	if !strings.HasPrefix(key, "-----BEGIN") {
+		if b, err := os.ReadFile(key); err == nil && strings.HasPrefix(string(b), "-----BEGIN") {
+			pem = string(b)
+		}
+	}
+	if !strings.HasPrefix(pem, "-----BEGIN") {
+		return &Tokens{method: jwt.SigningMethodHS256, signKey: []byte(key), verifyKey: []byte(key)}, nil
+	}
+
+	if strings.Contains(pem, "PRIVATE KEY") {
+		private, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(pem))
+		if err != nil {
+			return nil, err
+		}
+		return &Tokens{method: jwt.SigningMethodRS256, signKey: private, verifyKey: &private.PublicKey}, nil
+	}
+	public, err := jwt.ParseRSAPublicKeyFromPEM([]byte(pem))
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{method: jwt.SigningMethodRS256, verifyKey: public}, nil
+}
+
+// Sign issues a token holding claims, valid for ttl.
+func (t *Tokens) Sign(claims models.Claims, ttl time.Duration) (string, time.Time, error) {
+	if t.signKey == nil {
+		return "", time.Time{}, ErrTokenSigning
+	}
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims.Issuer = "adminDocker"
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	token, err := jwt.NewWithClaims(t.method, claims).SignedString(t.signKey)
+	return token, expiresAt, err
+}
+
+// Verify checks the signature and the validity dates of a token and returns its claims.
+func (t *Tokens) Verify(token string) (*models.Claims, error) {
+	claims := &models.Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return t.verifyKey, nil
+	}, jwt.WithValidMethods([]string{t.method.Alg()}), jwt.WithIssuedAt())
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	case err != nil:
+		return nil, ErrTokenInvalid
+	}
+	if claims.Subject == "" {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestTokensHS256(t *testing.T) {
+	tokens, err := NewTokens("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(key string, claims jwt.RegisteredClaims) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		return token
+	}
+	valid := jwt.RegisteredClaims{Subject: "alice", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+
+	claims, err := tokens.Verify(sign("secret", valid))
+	if err != nil || claims.Subject != "alice" {
+		t.Errorf("valid token rejected: %v", err)
+	}
+	if _, err := tokens.Verify(sign("other", valid)); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("wrong signature should be invalid, got %v", err)
+	}
+	expired := jwt.RegisteredClaims{Subject: "alice", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))}
+	if _, err := tokens.Verify(sign("secret", expired)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired token should be rejected, got %v", err)
+	}
+	if _, err := tokens.Verify(sign("secret", jwt.RegisteredClaims{})); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("token without subject should be invalid, got %v", err)
+	}
+	token, _, err := tokens.Sign(models.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "bob"}}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, err := tokens.Verify(token); err != nil || claims.Subject != "bob" {
+		t.Errorf("issued token rejected: %v", err)
+	}
+	if _, err := NewTokens(""); err == nil {
+		t.Error("an empty key should be refused")
+	}
+}
+
+func TestTokensRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	tokens, err := NewTokens(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.RegisteredClaims{Subject: "ci"}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if _, err := tokens.Verify(token); err != nil {
+		t.Errorf("valid RS256 token rejected: %v", err)
+	}
+
+	// A public key must not be usable as an HMAC secret
+	hmacToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(public)
+	if _, err := tokens.Verify(hmacToken); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("HS256 token should be rejected with an RSA key, got %v", err)
+	}
+	if _, _, err := tokens.Sign(models.Claims{}, time.Minute); !errors.Is(err, ErrTokenSigning) {
+		t.Errorf("a public key should not issue tokens, got %v", err)
+	}
+}
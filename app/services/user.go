@@ -0,0 +1,316 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+)
+
+// Minimal length of the passwords
+const passwordMinLength = 8
+
+// ErrBadCredentials is returned when a login fails, whatever the reason.
+var ErrBadCredentials = errdefs.Unauthorized(errors.New("invalid username or password"))
+
+// userRecord is a user as stored in the users file.
+// PasswordVersion is increased by every password change, to revoke the tokens issued before.
+type userRecord struct {
+	models.User
+	PasswordHash    string `json:"password_hash"`
+	PasswordVersion int    `json:"password_version,omitempty"`
+}
+
+// User manages the local accounts, stored in a JSON file.
+type User struct {
+	file     string
+	tokens   *Tokens
+	tokenTTL time.Duration
+	validate *validator.Validate
+	logs     *zerolog.Logger
+
+	mu    sync.RWMutex
+	users map[string]*userRecord
+	// Compared on unknown usernames so that a login takes the same time
+	dummyHash string
+}
+
+// NewServiceUser loads the users file. When it holds no user an admin account is
+// created, with adminPassword or a generated password written to the logs.
+func NewServiceUser(file string, tokens *Tokens, tokenTTL time.Duration, adminPassword string, logs *zerolog.Logger) (*User, error) {
+	u := &User{
+		file:     file,
+		tokens:   tokens,
+		tokenTTL: tokenTTL,
+		validate: validator.New(),
+		logs:     logs,
+		users:    make(map[string]*userRecord),
+	}
+
+	dummy, err := functions.HashAndSalt(functions.GeneratePassword(16, 2, 2, 2))
+	if err != nil {
+		return nil, err
+	}
+	u.dummyHash = string(dummy)
+
+	var records []*userRecord
+	if err := readJSONFile(file, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		// Accounts created before the roles could do everything
+		if record.Role == "" {
+			record.Role = models.RoleAdmin
+		}
+		u.users[strings.ToLower(record.Username)] = record
+	}
+
+	if len(u.users) == 0 {
+		generated := adminPassword == ""
+		if generated {
+			adminPassword = functions.GeneratePassword(16, 2, 2, 2)
+		}
+		if _, err := u.Create(models.UserSpec{Username: "admin", Password: adminPassword, Role: models.RoleAdmin}); err != nil {
+			return nil, err
+		}
+		if generated {
+			logs.Warn().Str("username", "admin").Str("password", adminPassword).Msg("No user found, admin account created")
+		}
+	}
+	return u, nil
+}
+
+// Login checks credentials and issues a token.
+func (u *User) Login(credentials models.Credentials) (models.Token, error) {
+	if err := u.validate.Struct(credentials); err != nil {
+		return models.Token{}, errdefs.InvalidParameter(err)
+	}
+
+	u.mu.RLock()
+	record, ok := u.users[strings.ToLower(credentials.Username)]
+	u.mu.RUnlock()
+
+	hash := u.dummyHash
+	if ok {
+		hash = record.PasswordHash
+	}
+	if err := functions.CheckPassword(credentials.Password, hash); err != nil || !ok {
+		return models.Token{}, ErrBadCredentials
+	}
+
+	claims := models.Claims{
+		RegisteredClaims:   jwt.RegisteredClaims{Subject: record.Username},
+		Role:               record.Role,
+		Scope:              record.Scope,
+		MustChangePassword: record.MustChangePassword,
+		PasswordVersion:    record.PasswordVersion,
+	}
+	token, expiresAt, err := u.tokens.Sign(claims, u.tokenTTL)
+	if err != nil {
+		return models.Token{}, err
+	}
+	return models.Token{
+		Token:              token,
+		TokenType:          "Bearer",
+		ExpiresAt:          expiresAt,
+		MustChangePassword: record.MustChangePassword,
+	}, nil
+}
+
+// Revoked tells if a token of a local user was issued for a former password.
+// Subjects unknown here come from tokens issued by another service.
+func (u *User) Revoked(claims *models.Claims) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	record, ok := u.users[strings.ToLower(claims.Subject)]
+	return ok && claims.PasswordVersion != record.PasswordVersion
+}
+
+// List returns the users sorted by username.
+func (u *User) List() []models.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]models.User, 0, len(u.users))
+	for _, record := range u.users {
+		users = append(users, record.User)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users
+}
+
+// Create adds a user.
+func (u *User) Create(spec models.UserSpec) (models.User, error) {
+	if err := u.validate.Struct(spec); err != nil {
+		return models.User{}, errdefs.InvalidParameter(err)
+	}
+	if !functions.IsUserNameValid(spec.Username) {
+		return models.User{}, errdefs.InvalidParameter(errors.New("invalid username, 4 to 254 letters are expected"))
+	}
+	if spec.Email != "" && !functions.IsEmailValid(spec.Email) {
+		return models.User{}, errdefs.InvalidParameter(errors.New("invalid email"))
+	}
+	if !functions.IsPasswordValid(spec.Password, passwordMinLength) {
+		return models.User{}, errInvalidPassword()
+	}
+	if spec.Role == "" {
+		spec.Role = models.RoleViewer
+	}
+	if !spec.Role.Valid() {
+		return models.User{}, errInvalidRole(spec.Role)
+	}
+	hash, err := functions.HashAndSalt(spec.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key := strings.ToLower(spec.Username)
+	if _, ok := u.users[key]; ok {
+		return models.User{}, errdefs.Conflict(fmt.Errorf("user %s already exists", spec.Username))
+	}
+	now := time.Now().UTC()
+	record := &userRecord{
+		User: models.User{
+			Username:  spec.Username,
+			Email:     spec.Email,
+			Role:      spec.Role,
+			Scope:     spec.Scope,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		PasswordHash: string(hash),
+	}
+	u.users[key] = record
+	if err := u.save(); err != nil {
+		delete(u.users, key)
+		return models.User{}, err
+	}
+	return record.User, nil
+}
+
+// Update changes the role and the scope of a user, applied to the tokens issued afterwards.
+func (u *User) Update(username string, update models.UserUpdate) (models.User, error) {
+	if err := u.validate.Struct(update); err != nil {
+		return models.User{}, errdefs.InvalidParameter(err)
+	}
+	if !update.Role.Valid() {
+		return models.User{}, errInvalidRole(update.Role)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	record, ok := u.users[strings.ToLower(username)]
+	if !ok {
+		return models.User{}, errdefs.NotFound(fmt.Errorf("user %s not found", username))
+	}
+	previous := *record
+	record.Role = update.Role
+	record.Scope = update.Scope
+	record.UpdatedAt = time.Now().UTC()
+	if err := u.save(); err != nil {
+		*record = previous
+		return models.User{}, err
+	}
+	return record.User, nil
+}
+
+// Get returns a user.
+func (u *User) Get(username string) (models.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	record, ok := u.users[strings.ToLower(username)]
+	if !ok {
+		return models.User{}, errdefs.NotFound(fmt.Errorf("user %s not found", username))
+	}
+	return record.User, nil
+}
+
+// ChangePassword replaces the password of a user, knowing the current one.
+func (u *User) ChangePassword(username string, change models.PasswordChange) error {
+	if err := u.validate.Struct(change); err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	if !functions.IsPasswordValid(change.NewPassword, passwordMinLength) {
+		return errInvalidPassword()
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	record, ok := u.users[strings.ToLower(username)]
+	if !ok {
+		return errdefs.NotFound(fmt.Errorf("user %s not found", username))
+	}
+	if err := functions.CheckPassword(change.OldPassword, record.PasswordHash); err != nil {
+		return errdefs.Forbidden(errors.New("current password is wrong"))
+	}
+	return u.setPassword(record, change.NewPassword, false)
+}
+
+// ResetPassword replaces the password of a user by a generated one, to be changed at next login.
+func (u *User) ResetPassword(username string) (models.PasswordReset, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	record, ok := u.users[strings.ToLower(username)]
+	if !ok {
+		return models.PasswordReset{}, errdefs.NotFound(fmt.Errorf("user %s not found", username))
+	}
+	password := functions.GeneratePassword(16, 2, 2, 2)
+	if err := u.setPassword(record, password, true); err != nil {
+		return models.PasswordReset{}, err
+	}
+	return models.PasswordReset{Username: record.Username, Password: password}, nil
+}
+
+// setPassword hashes and stores a password. Callers hold the lock.
+func (u *User) setPassword(record *userRecord, password string, mustChange bool) error {
+	hash, err := functions.HashAndSalt(password)
+	if err != nil {
+		return err
+	}
+	previous := *record
+	record.PasswordHash = string(hash)
+	record.MustChangePassword = mustChange
+	record.PasswordVersion++
+	record.UpdatedAt = time.Now().UTC()
+	if err := u.save(); err != nil {
+		*record = previous
+		return err
+	}
+	return nil
+}
+
+// save writes the users file atomically. Callers hold the lock.
+func (u *User) save() error {
+	records := make([]*userRecord, 0, len(u.users))
+	for _, record := range u.users {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Username < records[j].Username })
+
+	return writeJSONFile(u.file, records)
+}
+
+func errInvalidPassword() error {
+	return errdefs.InvalidParameter(fmt.Errorf("invalid password, at least %d characters with an upper case, a lower case, a digit and a special character are expected", passwordMinLength))
+}
+
+func errInvalidRole(role models.Role) error {
+	return errdefs.InvalidParameter(fmt.Errorf("invalid role %q, viewer, operator or admin are expected", role))
+}
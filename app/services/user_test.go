@@ -0,0 +1,94 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestUsers(t *testing.T) {
+	logs := zerolog.Nop()
+	tokens, _ := NewTokens("secret")
+	file := filepath.Join(t.TempDir(), "users.json")
+
+	users, err := NewServiceUser(file, tokens, time.Hour, "Adm1n!pass", &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := users.Login(models.Credentials{Username: "admin", Password: "Adm1n!pass"})
+	if err != nil {
+		t.Fatalf("admin login failed: %v", err)
+	}
+	if claims, err := tokens.Verify(token.Token); err != nil || claims.Subject != "admin" || claims.Role != models.RoleAdmin {
+		t.Errorf("issued token rejected or without the admin role: %v", err)
+	}
+	if _, err := users.Login(models.Credentials{Username: "admin", Password: "wrong"}); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("wrong password should be rejected, got %v", err)
+	}
+	if _, err := users.Login(models.Credentials{Username: "nobody", Password: "Adm1n!pass"}); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("unknown user should be rejected, got %v", err)
+	}
+
+	if _, err := users.Create(models.UserSpec{Username: "alice", Password: "weak"}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("weak password should be refused, got %v", err)
+	}
+	if _, err := users.Create(models.UserSpec{Username: "alice", Email: "alice@example.com", Password: "Al1ce!pass"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Create(models.UserSpec{Username: "Alice", Password: "Al1ce!pass"}); !errdefs.IsConflict(err) {
+		t.Errorf("duplicate user should conflict, got %v", err)
+	}
+	if _, err := users.Create(models.UserSpec{Username: "bob", Password: "B0b!passw", Role: "root"}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("unknown role should be refused, got %v", err)
+	}
+	update := models.UserUpdate{Role: models.RoleOperator, Scope: models.Scope{"team": "payments"}}
+	if updated, err := users.Update("alice", update); err != nil || updated.Role != models.RoleOperator {
+		t.Errorf("role update failed: %v", err)
+	}
+
+	before, err := users.Login(models.Credentials{Username: "alice", Password: "Al1ce!pass"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reset, err := users.ResetPassword("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, _ := tokens.Verify(before.Token); claims == nil || !users.Revoked(claims) {
+		t.Error("a token issued before a reset should be revoked")
+	}
+	token, err = users.Login(models.Credentials{Username: "alice", Password: reset.Password})
+	if err != nil || !token.MustChangePassword {
+		t.Errorf("reset password should log in and require a change: %v", err)
+	}
+	claims, _ := tokens.Verify(token.Token)
+	if claims == nil || claims.Scope["team"] != "payments" || !claims.MustChangePassword || users.Revoked(claims) {
+		t.Errorf("token should carry the scope of the user and the password change, got %v", claims)
+	}
+	change := models.PasswordChange{OldPassword: reset.Password, NewPassword: "N3w!passw"}
+	if err := users.ChangePassword("alice", change); err != nil {
+		t.Fatal(err)
+	}
+	if !users.Revoked(claims) {
+		t.Error("a token issued before a password change should be revoked")
+	}
+
+	// Reloading the file keeps the accounts
+	reloaded, err := NewServiceUser(file, tokens, time.Hour, "", &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err = reloaded.Login(models.Credentials{Username: "alice", Password: "N3w!passw"})
+	if err != nil || token.MustChangePassword {
+		t.Errorf("changed password not persisted: %v", err)
+	}
+	if len(reloaded.List()) != 2 {
+		t.Errorf("expected 2 users, got %d", len(reloaded.List()))
+	}
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+)
+
+// volumeFilterKeys maps the filter keys of the list endpoint to the daemon filter
+// they are pushed down to.
+var volumeFilterKeys = map[string]string{
+	"name":   "name",
+	"driver": "driver",
+	"label":  "label",
+	"orphan": "dangling",
+}
+
+type Volume struct {
+	clientDocker DockerBackend
+	validate     *validator.Validate
+	logs         *zerolog.Logger
+}
+
+func NewServiceVolume(backend DockerBackend, logs *zerolog.Logger) *Volume {
+	return &Volume{
+		clientDocker: backend,
+		validate:     validator.New(),
+		logs:         logs,
+	}
+}
+
+// List returns the volumes of scope matching the filters, filter_like and search clauses
+// of params with their usage, sorted by its sort clause.
+func (v *Volume) List(ctx context.Context, params *models.QueryParams, scope models.Scope) ([]models.VolumeUsage, error) {
+	exact, err := parseClauses(params.FilterClause, volumeFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	like, err := parseClauses(params.FilterLikeClause, volumeFilterKeys)
+	if err != nil {
+		return nil, err
+	}
+	if err := boolClauses(exact, "orphan"); err != nil {
+		return nil, err
+	}
+	if err := boolClauses(like, "orphan"); err != nil {
+		return nil, err
+	}
+
+	// Push the exact filters down to the daemon, they are checked again below
+	// as the daemon is more lenient (name matches a part of the name).
+	options := volume.ListOptions{Filters: filters.NewArgs()}
+	for key, values := range exact {
+		// The daemon refuses several dangling values, they are checked below
+		if key == "orphan" && len(values) > 1 {
+			continue
+		}
+		for _, value := range values {
+			options.Filters.Add(volumeFilterKeys[key], value)
+		}
+	}
+	for key, value := range scope {
+		options.Filters.Add("label", key+"="+value)
+	}
+
+	response, err := v.clientDocker.VolumeList(ctx, options)
+	if err != nil {
+		return nil, logError(v.logs, err)
+	}
+	sizes, mounts, err := v.usage(ctx, scope)
+	if err != nil {
+		return nil, logError(v.logs, err)
+	}
+
+	volumes := []models.VolumeUsage{}
+	for _, vol := range response.Volumes {
+		if vol == nil || !scope.Matches(vol.Labels) {
+			continue
+		}
+		usage := volumeUsage(*vol, sizes, mounts)
+		fields := func(key string) []string { return volumeFields(&usage, key) }
+		if matchClauses(exact, false, fields, matchVolumeField) && matchClauses(like, true, fields, matchVolumeField) && matchVolumeSearch(&usage, params.SearchClause) {
+			volumes = append(volumes, usage)
+		}
+	}
+	if err := sortItems(volumes, params.SortClause); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+// Inspect returns a volume of scope given its name, with its usage. Volumes out of
+// the scope are reported as not found.
+func (v *Volume) Inspect(ctx context.Context, name string, scope models.Scope) (models.VolumeUsage, error) {
+	vol, err := v.clientDocker.VolumeInspect(ctx, name)
+	if err != nil {
+		return models.VolumeUsage{}, logError(v.logs, err)
+	}
+	if !scope.Matches(vol.Labels) {
+		return models.VolumeUsage{}, errdefs.NotFound(fmt.Errorf("get %s: no such volume", name))
+	}
+	sizes, mounts, err := v.usage(ctx, scope)
+	if err != nil {
+		return models.VolumeUsage{}, logError(v.logs, err)
+	}
+	return volumeUsage(vol, sizes, mounts), nil
+}
+
+// Create creates the volume of spec. Unlike the daemon, an existing name is a conflict
+// rather than the existing volume.
+func (v *Volume) Create(ctx context.Context, spec *models.VolumeSpec) (models.VolumeUsage, error) {
+	if err := v.validate.Struct(spec); err != nil {
+		return models.VolumeUsage{}, errdefs.InvalidParameter(err)
+	}
+	if spec.Name != "" {
+		if _, err := v.clientDocker.VolumeInspect(ctx, spec.Name); err == nil {
+			return models.VolumeUsage{}, errdefs.Conflict(fmt.Errorf("volume %s already exists", spec.Name))
+		} else if !errdefs.IsNotFound(err) {
+			return models.VolumeUsage{}, logError(v.logs, err)
+		}
+	}
+
+	vol, err := v.clientDocker.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       spec.Name,
+		Driver:     spec.Driver,
+		DriverOpts: spec.DriverOpts,
+		Labels:     spec.Labels,
+	})
+	if err != nil {
+		return models.VolumeUsage{}, logError(v.logs, err)
+	}
+	// The daemon only tells the size of a volume with the disk usage
+	return v.Inspect(ctx, vol.Name, nil)
+}
+
+// Remove removes a volume of scope mounted by no container. force ignores a missing volume.
+func (v *Volume) Remove(ctx context.Context, name string, force bool, scope models.Scope) error {
+	if _, err := v.Inspect(ctx, name, scope); err != nil {
+		if force && errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return logError(v.logs, v.clientDocker.VolumeRemove(ctx, name, force))
+}
+
+// usage returns the size of the volumes, the daemon only computes it for the disk usage,
+// and the containers mounting them: all of them to tell the orphans, but only the
+// containers of scope are listed.
+func (v *Volume) usage(ctx context.Context, scope models.Scope) (map[string]int64, map[string][]models.VolumeContainer, error) {
+	usage, err := v.clientDocker.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return nil, nil, err
+	}
+	sizes := make(map[string]int64, len(usage.Volumes))
+	for _, vol := range usage.Volumes {
+		sizes[vol.Name] = volumeUsageSize(vol.UsageData)
+	}
+
+	containers, err := v.clientDocker.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	mounts := make(map[string][]models.VolumeContainer)
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Type != mount.TypeVolume || m.Name == "" {
+				continue
+			}
+			mounted := models.VolumeContainer{}
+			// Out of the scope containers only tell the volume is used
+			if scope.Matches(c.Labels) {
+				mounted = models.VolumeContainer{
+					ID:          c.ID,
+					Name:        containerName(&c),
+					State:       c.State,
+					Destination: m.Destination,
+					RW:          m.RW,
+				}
+			}
+			mounts[m.Name] = append(mounts[m.Name], mounted)
+		}
+	}
+	return sizes, mounts, nil
+}
+
+// volumeUsage adds to a volume its size and the containers of scope mounting it, by name.
+func volumeUsage(vol volume.Volume, sizes map[string]int64, mounts map[string][]models.VolumeContainer) models.VolumeUsage {
+	usage := models.VolumeUsage{Volume: vol, Size: -1, Orphan: len(mounts[vol.Name]) == 0, Containers: []models.VolumeContainer{}}
+	if size, ok := sizes[vol.Name]; ok {
+		usage.Size = size
+	}
+	for _, mounted := range mounts[vol.Name] {
+		if mounted.ID != "" {
+			usage.Containers = append(usage.Containers, mounted)
+		}
+	}
+	sort.Slice(usage.Containers, func(i, j int) bool { return usage.Containers[i].Name < usage.Containers[j].Name })
+	return usage
+}
+
+// volumeUsageSize returns the size of a volume, -1 when unknown like the daemon.
+func volumeUsageSize(data *volume.UsageData) int64 {
+	if data == nil {
+		return -1
+	}
+	return data.Size
+}
+
+func matchVolumeField(key, field, value string) bool {
+	if key == "label" {
+		return matchLabel(field, value)
+	}
+	return field == value
+}
+
+// volumeFields returns the values of a volume a filter key is checked against.
+func volumeFields(vol *models.VolumeUsage, key string) []string {
+	switch key {
+	case "name":
+		return []string{vol.Name}
+	case "driver":
+		return []string{vol.Driver}
+	case "label":
+		return labelFields(vol.Labels)
+	case "orphan":
+		return []string{strconv.FormatBool(vol.Orphan)}
+	}
+	return nil
+}
+
+// matchVolumeSearch tells if every keyword appears in the name, the driver, a label
+// or the name of a container mounting a volume.
+func matchVolumeSearch(vol *models.VolumeUsage, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	fields := []string{vol.Name, vol.Driver}
+	fields = append(fields, volumeFields(vol, "label")...)
+	for _, mounted := range vol.Containers {
+		fields = append(fields, mounted.Name)
+	}
+	return matchSearch(keywords, fields...)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestVolumes(t *testing.T) {
+	logs := zerolog.Nop()
+	engine := NewFakeEngine()
+	volumes := NewServiceVolume(engine, &logs)
+	ctx := context.Background()
+	team := models.Scope{"team": "a"}
+
+	created, err := volumes.Create(ctx, &models.VolumeSpec{Name: "data", Labels: map[string]string{"team": "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "data" || created.Driver != "local" || !created.Orphan || created.Size != 0 {
+		t.Errorf("an empty local volume expected, got %+v", created)
+	}
+	if _, err := volumes.Create(ctx, &models.VolumeSpec{Name: "data"}); !errdefs.IsConflict(err) {
+		t.Errorf("an existing name should conflict, got %v", err)
+	}
+	if _, err := volumes.Create(ctx, &models.VolumeSpec{Driver: "nfs"}); !errdefs.IsNotFound(err) {
+		t.Errorf("an unknown driver should not be found, got %v", err)
+	}
+
+	db, err := engine.ContainerCreate(ctx, &container.Config{Image: "redis:latest", Labels: map[string]string{"team": "a"}},
+		&container.HostConfig{Binds: []string{"data:/data"}}, nil, nil, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.ContainerCreate(ctx, &container.Config{Image: "nginx:latest"},
+		&container.HostConfig{Binds: []string{"data:/backup:ro", "logs:/logs"}}, nil, nil, "backup"); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := volumes.Inspect(ctx, "data", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Orphan || len(usage.Containers) != 2 || usage.Containers[0].Name != "backup" || usage.Containers[0].RW || usage.Containers[1].ID != db.ID {
+		t.Errorf("data should be mounted by backup read-only and db, got %+v", usage.Containers)
+	}
+	scoped, err := volumes.Inspect(ctx, "data", team)
+	if err != nil || scoped.Orphan || len(scoped.Containers) != 1 || scoped.Containers[0].Destination != "/data" {
+		t.Errorf("a scoped caller should only see db, got %+v %v", scoped.Containers, err)
+	}
+	if _, err := volumes.Inspect(ctx, "logs", team); !errdefs.IsNotFound(err) {
+		t.Errorf("a volume out of the scope should not be found, got %v", err)
+	}
+
+	if _, err := engine.VolumeCreate(ctx, volume.CreateOptions{Name: "cache"}); err != nil {
+		t.Fatal(err)
+	}
+	orphans, err := volumes.List(ctx, &models.QueryParams{FilterClause: []string{"orphan:true"}}, nil)
+	if err != nil || len(orphans) != 1 || orphans[0].Name != "cache" {
+		t.Errorf("cache should be the only orphan, got %+v %v", orphans, err)
+	}
+	if _, err := volumes.List(ctx, &models.QueryParams{FilterClause: []string{"orphan:maybe"}}, nil); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("orphan should be a boolean, got %v", err)
+	}
+	sorted, _ := volumes.List(ctx, &models.QueryParams{SortClause: []string{"-size"}}, nil)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Size > sorted[i-1].Size {
+			t.Errorf("volumes should be sorted by decreasing size, got %d after %d", sorted[i].Size, sorted[i-1].Size)
+		}
+	}
+	if listed, _ := volumes.List(ctx, &models.QueryParams{SearchClause: []string{"backup"}}, nil); len(listed) != 2 {
+		t.Errorf("the volumes of backup should be found by its name, got %+v", listed)
+	}
+
+	if err := volumes.Remove(ctx, "data", false, team); !errdefs.IsConflict(err) {
+		t.Errorf("a mounted volume should not be removed, got %v", err)
+	}
+	if err := volumes.Remove(ctx, "cache", false, team); !errdefs.IsNotFound(err) {
+		t.Errorf("a volume out of the scope should not be removed, got %v", err)
+	}
+	if err := volumes.Remove(ctx, "cache", false, nil); err != nil {
+		t.Errorf("an orphan should be removed, got %v", err)
+	}
+	if err := volumes.Remove(ctx, "cache", true, nil); err != nil {
+		t.Errorf("force should ignore a missing volume, got %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"adminDocker/app/functions"
+	"adminDocker/app/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+)
+
+// Headers of the webhook requests
+const (
+	WebhookSignatureHeader = "X-AdminDocker-Signature"
+	WebhookEventHeader     = "X-AdminDocker-Event"
+	WebhookDeliveryHeader  = "X-AdminDocker-Delivery"
+)
+
+const (
+	// webhookAttempts is the number of attempts of a delivery
+	webhookAttempts = 5
+	// webhookBackoff is the delay before the first retry, doubled on each retry
+	webhookBackoff = time.Second
+	// webhookDeliveries is the number of deliveries kept per webhook
+	webhookDeliveries = 100
+)
+
+// webhookEventFilters are the daemon events the webhooks are notified of.
+var webhookEventFilters = []string{
+	"type:container",
+	"action:die",
+	"action:restart",
+	"action:oom",
+	"action:health_status",
+}
+
+// webhookRecord is a webhook as stored in the webhooks file.
+type webhookRecord struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
+
+// Webhook posts the container state changes to the registered webhooks, stored
+// in a JSON file. The deliveries are only kept in memory.
+type Webhook struct {
+	file         string
+	eventService *Event
+	client       *http.Client
+	validate     *validator.Validate
+	logs         *zerolog.Logger
+	attempts     int
+	backoff      time.Duration
+
+	mu         sync.Mutex
+	hooks      map[string]*webhookRecord
+	deliveries map[string][]*models.WebhookDelivery
+}
+
+// NewServiceWebhook loads the webhooks file. Run starts the notifications.
+func NewServiceWebhook(file string, eventService *Event, logs *zerolog.Logger) (*Webhook, error) {
+	w := &Webhook{
+		file:         file,
+		eventService: eventService,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		validate:     validator.New(),
+		logs:         logs,
+		attempts:     webhookAttempts,
+		backoff:      webhookBackoff,
+		hooks:        make(map[string]*webhookRecord),
+		deliveries:   make(map[string][]*models.WebhookDelivery),
+	}
+
+	var records []*webhookRecord
+	if err := readJSONFile(file, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		w.hooks[record.ID] = record
+	}
+	return w, nil
+}
+
+// Create registers a webhook for owner.
+func (w *Webhook) Create(owner string, spec models.WebhookSpec) (models.WebhookCreated, error) {
+	if err := w.validate.Struct(spec); err != nil {
+		return models.WebhookCreated{}, errdefs.InvalidParameter(err)
+	}
+	if spec.Secret == "" {
+		spec.Secret = randomHex(24)
+	}
+
+	record := &webhookRecord{
+		Webhook: models.Webhook{
+			ID:        randomHex(6),
+			URL:       spec.URL,
+			Events:    spec.Events,
+			Scope:     spec.Scope,
+			Owner:     owner,
+			CreatedAt: time.Now().UTC(),
+		},
+		Secret: spec.Secret,
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks[record.ID] = record
+	if err := w.save(); err != nil {
+		delete(w.hooks, record.ID)
+		return models.WebhookCreated{}, err
+	}
+	return models.WebhookCreated{Webhook: record.Webhook, Secret: record.Secret}, nil
+}
+
+// List returns the webhooks, the most recent first.
+func (w *Webhook) List() []models.Webhook {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	hooks := make([]models.Webhook, 0, len(w.hooks))
+	for _, record := range w.hooks {
+		hooks = append(hooks, record.Webhook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].CreatedAt.After(hooks[j].CreatedAt) })
+	return hooks
+}
+
+// Get returns a webhook.
+func (w *Webhook) Get(id string) (models.Webhook, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	record, ok := w.hooks[id]
+	if !ok {
+		return models.Webhook{}, errdefs.NotFound(fmt.Errorf("webhook %s not found", id))
+	}
+	return record.Webhook, nil
+}
+
+// Delete removes a webhook and its deliveries.
+func (w *Webhook) Delete(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	record, ok := w.hooks[id]
+	if !ok {
+		return errdefs.NotFound(fmt.Errorf("webhook %s not found", id))
+	}
+	delete(w.hooks, id)
+	if err := w.save(); err != nil {
+		w.hooks[id] = record
+		return err
+	}
+	delete(w.deliveries, id)
+	return nil
+}
+
+// Deliveries returns the last deliveries of a webhook, the most recent first.
+func (w *Webhook) Deliveries(id string) ([]models.WebhookDelivery, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.hooks[id]; !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("webhook %s not found", id))
+	}
+	deliveries := make([]models.WebhookDelivery, 0, len(w.deliveries[id]))
+	for i := len(w.deliveries[id]) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, *w.deliveries[id][i])
+	}
+	return deliveries, nil
+}
+
+// Run notifies the webhooks of the daemon events until ctx is done,
+// subscribing again when the event stream breaks.
+func (w *Webhook) Run(ctx context.Context) {
+	for {
+		err := w.eventService.Subscribe(ctx, webhookEventFilters, nil, func(event models.Event) error {
+			w.dispatch(ctx, event)
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		w.logs.Warn().Err(err).Msg("Webhooks event stream closed, subscribing again")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// dispatch starts the deliveries of an event to the matching webhooks.
+func (w *Webhook) dispatch(ctx context.Context, event models.Event) {
+	name := webhookEvent(event)
+	if name == "" {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, record := range w.hooks {
+		if !record.Scope.Matches(event.Attributes) || (len(record.Events) > 0 && !functions.Contains(record.Events, name)) {
+			continue
+		}
+		delivery := &models.WebhookDelivery{
+			ID:          randomHex(8),
+			WebhookID:   record.ID,
+			Event:       name,
+			ContainerID: event.ActorID,
+			Name:        event.Name,
+			Status:      "pending",
+			CreatedAt:   time.Now().UTC(),
+		}
+		deliveries := append(w.deliveries[record.ID], delivery)
+		if len(deliveries) > webhookDeliveries {
+			deliveries = deliveries[len(deliveries)-webhookDeliveries:]
+		}
+		w.deliveries[record.ID] = deliveries
+
+		payload := models.WebhookPayload{
+			DeliveryID: delivery.ID,
+			WebhookID:  record.ID,
+			Event:      name,
+			Container:  event,
+			SentAt:     time.Now().UTC(),
+		}
+		go w.deliver(ctx, *record, delivery, payload)
+	}
+}
+
+// deliver posts a payload, retrying with an exponential backoff on network errors,
+// 429 and 5xx responses.
+func (w *Webhook) deliver(ctx context.Context, hook webhookRecord, delivery *models.WebhookDelivery, payload models.WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.logs.Error().Err(err).Msg("")
+		return
+	}
+	mac := hmac.New(sha256.New, []byte(hook.Secret))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	backoff := w.backoff
+	for attempt := 1; attempt <= w.attempts; attempt++ {
+		statusCode, err := w.post(ctx, hook.URL, body, signature, payload)
+
+		w.mu.Lock()
+		delivery.Attempts = attempt
+		delivery.StatusCode = statusCode
+		delivery.Error = ""
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		retry := err != nil && (statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500)
+		switch {
+		case err == nil:
+			now := time.Now().UTC()
+			delivery.Status = "delivered"
+			delivery.DeliveredAt = &now
+		case !retry || attempt == w.attempts:
+			delivery.Status = "failed"
+		}
+		w.mu.Unlock()
+
+		if err == nil {
+			return
+		}
+		if !retry || attempt == w.attempts {
+			w.logs.Warn().Err(err).Str("webhook", hook.ID).Str("delivery", delivery.ID).Msg("Webhook delivery failed")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a payload, an error being returned for the non 2xx responses.
+func (w *Webhook) post(ctx context.Context, url string, body []byte, signature string, payload models.WebhookPayload) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "adminDocker-webhook")
+	req.Header.Set(WebhookSignatureHeader, signature)
+	req.Header.Set(WebhookEventHeader, payload.Event)
+	req.Header.Set(WebhookDeliveryHeader, payload.DeliveryID)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// save writes the webhooks file. Callers hold the lock.
+func (w *Webhook) save() error {
+	records := make([]*webhookRecord, 0, len(w.hooks))
+	for _, record := range w.hooks {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	return writeJSONFile(w.file, records)
+}
+
+// webhookEvent names the webhook event of a daemon event, empty when none.
+func webhookEvent(event models.Event) string {
+	switch event.Action {
+	case "die", "restart", "oom":
+		return event.Action
+	case "health_status: unhealthy":
+		return "unhealthy"
+	}
+	return ""
+}
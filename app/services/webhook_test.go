@@ -0,0 +1,93 @@
+package services
+
+import (
+	"adminDocker/app/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/rs/zerolog"
+)
+
+func TestWebhooks(t *testing.T) {
+	logs := zerolog.Nop()
+	c, engine := newTestContainer()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan models.WebhookPayload, 8)
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// The first attempt fails to exercise the retries
+		if atomic.AddInt32(&calls, 1) == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		if r.Header.Get(WebhookSignatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("wrong signature %q", r.Header.Get(WebhookSignatureHeader))
+		}
+		var payload models.WebhookPayload
+		_ = json.Unmarshal(body, &payload)
+		received <- payload
+	}))
+	defer receiver.Close()
+
+	webhooks, err := NewServiceWebhook(filepath.Join(t.TempDir(), "webhooks.json"), NewServiceEvent(engine, &logs), &logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks.backoff = 10 * time.Millisecond
+
+	if _, err := webhooks.Create("admin", models.WebhookSpec{URL: receiver.URL, Events: []string{"start"}}); !errdefs.IsInvalidParameter(err) {
+		t.Errorf("unknown event should be refused, got %v", err)
+	}
+	hook, err := webhooks.Create("admin", models.WebhookSpec{URL: receiver.URL, Secret: "s3cret", Events: []string{"die"}, Scope: models.Scope{"team": "payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go webhooks.Run(ctx)
+	time.Sleep(50 * time.Millisecond)
+
+	// Out of scope, then in scope
+	_ = c.Stop(ctx, "fake-nginx", nil, "")
+	if _, err := c.Run(ctx, &models.ContainerSpec{Image: "nginx", Name: "payments-api", Labels: map[string]string{"team": "payments"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Stop(ctx, "payments-api", nil, "")
+
+	select {
+	case payload := <-received:
+		if payload.Event != "die" || payload.Container.Name != "payments-api" || payload.WebhookID != hook.ID {
+			t.Errorf("die of payments-api expected, got %+v", payload)
+		}
+	case <-ctx.Done():
+		t.Fatal("missing delivery")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	deliveries, err := webhooks.Deliveries(hook.ID)
+	if err != nil || len(deliveries) != 1 || deliveries[0].Status != "delivered" || deliveries[0].Attempts != 2 {
+		t.Errorf("one delivery after a retry expected, got %+v: %v", deliveries, err)
+	}
+
+	if err := webhooks.Delete(hook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := webhooks.Deliveries(hook.ID); !errdefs.IsNotFound(err) {
+		t.Errorf("deleted webhook should be not found, got %v", err)
+	}
+}
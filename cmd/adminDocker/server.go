@@ -1,8 +1,20 @@
 package main
 
 import (
+	"adminDocker/app/middlewares"
+	"adminDocker/app/routes/apikeys"
+	"adminDocker/app/routes/audit"
 	"adminDocker/app/routes/dockers"
+	"adminDocker/app/routes/events"
+	"adminDocker/app/routes/images"
+	"adminDocker/app/routes/networks"
+	"adminDocker/app/routes/projects"
+	"adminDocker/app/routes/system"
+	"adminDocker/app/routes/users"
+	"adminDocker/app/routes/volumes"
+	"adminDocker/app/routes/webhooks"
 	"adminDocker/app/server"
+	"adminDocker/app/services"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -23,6 +35,7 @@ func newAdminDockerServer() error {
 	srv := &server.AdminDocker{}
 
 	srv.ParseParameters()
+	server.SetServer(srv)
 
 	// log format definition
 	switch srv.LogFormat {
@@ -37,11 +50,89 @@ func newAdminDockerServer() error {
 	// setup router
 	srv.Router = setupRouter()
 
-	err := dockers.SetupRouter(srv.Router, &log.Logger)
+	backend, err := services.NewDockerBackend(srv.DockerFake)
+	if err != nil {
+		return err
+	}
+	if srv.DockerFake {
+		log.Warn().Msg("DOCKER_FAKE is set, using the in-memory Docker engine")
+	}
+
+	tokens, err := services.NewTokens(srv.TokenKey)
+	if err != nil {
+		return err
+	}
+	userService, err := services.NewServiceUser(srv.UsersFile, tokens, srv.TokenTTL, srv.AdminPassword, &log.Logger)
+	if err != nil {
+		return err
+	}
+	apiKeys, err := services.NewServiceAPIKey(srv.APIKeysFile, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	auditService, err := services.NewServiceAudit(srv.AuditFile, srv.AuditMaxEntries, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	public := srv.Router.Group("/v1")
+	v1 := srv.Router.Group("/v1", middlewares.Auth(tokens, userService, apiKeys), middlewares.Audit(auditService))
+
+	err = users.SetupRouter(public, v1, userService, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = audit.SetupRouter(v1, auditService, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = apikeys.SetupRouter(v1, apiKeys, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = dockers.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = images.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = networks.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = projects.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = volumes.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = system.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = events.SetupRouter(v1, backend, &log.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = webhooks.SetupRouter(v1, backend, &log.Logger)
 	if err != nil {
 		return err
 	}
-	server.SetServer(srv)
 
 	return nil
 }